@@ -25,6 +25,7 @@ func main() {
 	fmt.Printf("Playlist: %s\n", playlist.Title)
 	fmt.Printf("Description: %s\n", playlist.Description)
 	fmt.Printf("Total Items: %d\n", playlist.TotalItems)
+	fmt.Printf("Fetched Items: %d\n", playlist.FetchedCount)
 	fmt.Printf("Views: %d\n", playlist.Views)
 	fmt.Printf("Found %d items\n", len(playlist.Items))
 	fmt.Println()
@@ -147,6 +148,8 @@ func main() {
 			fmt.Printf("%d. %s\n", i+1, item.Name)
 			fmt.Printf("   ID: %s\n", item.ID)
 			fmt.Printf("   URL: %s\n", item.URL)
+			fmt.Printf("   Video Count: %d\n", item.Length)
+			fmt.Printf("   Published: %s\n", item.UploadedAt)
 			if item.Owner != nil {
 				fmt.Printf("   Owner: %s\n", item.Owner.Name)
 				fmt.Printf("   Owner Verified: %t\n", item.Owner.Verified)
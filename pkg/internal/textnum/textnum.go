@@ -0,0 +1,77 @@
+package textnum
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var humanNumberRegex = regexp.MustCompile(`(?i)(\d[\d,. ]*)\s*(k|m|b|thousand|million|billion)?\b`)
+
+var multipliers = map[string]float64{
+	"k":        1e3,
+	"thousand": 1e3,
+	"m":        1e6,
+	"million":  1e6,
+	"b":        1e9,
+	"billion":  1e9,
+}
+
+func Parse(text string) (int, bool) {
+	match := humanNumberRegex.FindStringSubmatch(text)
+	if len(match) < 2 {
+		return 0, false
+	}
+
+	numStr := strings.TrimSpace(match[1])
+	suffix := strings.ToLower(match[2])
+
+	if suffix == "" {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, numStr)
+		num, err := strconv.Atoi(digits)
+		if err != nil {
+			return 0, false
+		}
+		return num, true
+	}
+
+	numStr = strings.ReplaceAll(numStr, " ", "")
+	if strings.Contains(numStr, ".") || strings.Count(numStr, ",") > 1 || isThousandsComma(numStr) {
+		numStr = strings.ReplaceAll(numStr, ",", "")
+	} else {
+		numStr = strings.Replace(numStr, ",", ".", 1)
+	}
+	num, err := strconv.ParseFloat(numStr, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(math.Round(num * multipliers[suffix])), true
+}
+
+func isThousandsComma(numStr string) bool {
+	i := strings.Index(numStr, ",")
+	return i > 0 && len(numStr)-i-1 == 3
+}
+
+func ParseDuration(text string) (int, bool) {
+	parts := strings.Split(strings.TrimSpace(text), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+
+	seconds := 0
+	for _, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return 0, false
+		}
+		seconds = seconds*60 + value
+	}
+	return seconds, true
+}
@@ -0,0 +1,53 @@
+package textnum
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+		ok   bool
+	}{
+		{"1,234 views", 1234, true},
+		{"12 345 views", 12345, true},
+		{"1.5K views", 1500, true},
+		{"1,5K views", 1500, true},
+		{"2.3M subscribers", 2300000, true},
+		{"1,234.5K views", 1234500, true},
+		{"1,234,567K views", 1234567000, true},
+		{"1,234K", 1234000, true},
+		{"1.2M", 1200000, true},
+		{"3.4B views", 3400000000, true},
+		{"42", 42, true},
+		{"3 million views", 3000000, true},
+		{"1.2B views", 1200000000, true},
+		{"No views", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Parse(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %d, %v; want %d, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+		ok   bool
+	}{
+		{"3:25", 205, true},
+		{"1:02:03", 3723, true},
+		{"LIVE", 0, false},
+		{"1:2:3:4", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseDuration(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseDuration(%q) = %d, %v; want %d, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
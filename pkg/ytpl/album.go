@@ -0,0 +1,290 @@
+package ytpl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Victiniiiii/ytpl-ytsr-go/pkg/internal/textnum"
+)
+
+const (
+	BaseMusicURL    = "https://music.youtube.com/"
+	BaseMusicAPIURL = "https://music.youtube.com/youtubei/v1/browse?prettyPrint=false"
+
+	musicClientName    = "WEB_REMIX"
+	musicClientVersion = "1.20240605.01.00"
+)
+
+func getAlbum(plistID string, opts *Options) (*PlaylistInfo, error) {
+	apiKey, context, err := musicConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{
+		"context":  postContext(context, opts),
+		"browseId": "VL" + plistID,
+	}
+
+	jsonResp, err := doPost(musicAPIURL(apiKey), opts, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alertError(jsonResp); err != nil {
+		return nil, err
+	}
+
+	shelf := findRenderer(jsonResp["contents"], "musicPlaylistShelfRenderer")
+	if shelf == nil {
+		return nil, fmt.Errorf("%w: could not find album shelf", ErrParseFailure)
+	}
+
+	rawTrackList, ok := shelf["contents"].([]interface{})
+	if !ok || len(rawTrackList) == 0 {
+		return nil, ErrEmptyPlaylist
+	}
+
+	resp_info := &PlaylistInfo{
+		ID:      plistID,
+		URL:     fmt.Sprintf("%slist=%s", BasePlistURL, plistID),
+		APIKey:  apiKey,
+		Context: context,
+	}
+	if opts.KeepRawJSON {
+		resp_info.Raw = jsonResp
+	}
+
+	header := findRenderer(jsonResp, "musicResponsiveHeaderRenderer")
+	if header == nil {
+		header = findRenderer(jsonResp["header"], "musicDetailHeaderRenderer")
+	}
+	if header != nil {
+		parseAlbumHeader(header, resp_info)
+	}
+
+	resp_info.Items, err = parseItems(rawTrackList, opts, &resp_info.Stats)
+	if err != nil {
+		return nil, err
+	}
+	opts.remaining -= len(resp_info.Items)
+
+	token := pageToken(shelfContinuation(shelf), opts)
+	err = firstPage(resp_info, opts)
+	if err == nil && token != "" && opts.remaining >= 1 && !opts.cutoffReached && !opts.truncated && !opts.singlePage {
+		var nestedResp []PlaylistItem
+		nestedResp, token, err = parsePage2(apiKey, token, context, opts, &resp_info.Stats)
+		resp_info.Items = append(resp_info.Items, nestedResp...)
+	}
+	resp_info.NextToken = token
+
+	if resp_info.Owner != nil {
+		for i := range resp_info.Items {
+			if resp_info.Items[i].Author == "" {
+				resp_info.Items[i].Author = resp_info.Owner.Name
+				resp_info.Items[i].AuthorURL = resp_info.Owner.URL
+			}
+		}
+	}
+	if resp_info.TotalItems == 0 {
+		resp_info.TotalItems = len(resp_info.Items)
+	}
+	resp_info.FetchedCount = len(resp_info.Items)
+
+	return resp_info, err
+}
+
+func musicConfig(opts *Options) (string, Context, error) {
+	context := Context{}
+	context.Client.ClientName = musicClientName
+	context.Client.ClientVersion = musicClientVersion
+
+	body, err := doGet(BaseMusicURL, opts)
+	var dryErr *dryRunError
+	if errors.Is(err, ErrBudgetExceeded) || errors.As(err, &dryErr) {
+		return "", context, err
+	}
+	if err != nil {
+		return "", context, nil
+	}
+
+	cfg := extractYtcfg(string(body))
+	apiKey, _ := cfg["INNERTUBE_API_KEY"].(string)
+	if version, _ := cfg["INNERTUBE_CLIENT_VERSION"].(string); version != "" {
+		context.Client.ClientVersion = version
+	}
+	if visitorData, ok := cfg["VISITOR_DATA"].(string); ok {
+		context.Client.VisitorData = visitorData
+	}
+
+	return apiKey, context, nil
+}
+
+func musicAPIURL(apiKey string) string {
+	if apiKey == "" {
+		return BaseMusicAPIURL
+	}
+	return BaseMusicAPIURL + "&key=" + apiKey
+}
+
+func parseAlbumHeader(header map[string]interface{}, info *PlaylistInfo) {
+	info.Title = parseText(header["title"])
+	info.TotalItems = parseNumFromText(header["secondSubtitle"])
+
+	if description, ok := header["description"].(map[string]interface{}); ok {
+		if shelf, ok := description["musicDescriptionShelfRenderer"].(map[string]interface{}); ok {
+			info.Description = parseText(shelf["description"])
+		} else {
+			info.Description = parseText(description)
+		}
+	}
+
+	if thumbnails := findRenderer(header["thumbnail"], "thumbnail"); thumbnails != nil {
+		if thumbnailList, ok := thumbnails["thumbnails"].([]interface{}); ok {
+			for _, thumbnail := range parseThumbnails(thumbnailList) {
+				if thumbnail.Width > info.Thumbnail.Width {
+					info.Thumbnail = thumbnail
+				}
+			}
+		}
+	}
+
+	byline, _ := header["straplineTextOne"].(map[string]interface{})
+	if byline == nil {
+		byline, _ = header["subtitle"].(map[string]interface{})
+	}
+	runs, _ := byline["runs"].([]interface{})
+	for _, run := range runs {
+		runMap, _ := run.(map[string]interface{})
+		navEndpoint, ok := runMap["navigationEndpoint"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		owner := &Owner{}
+		owner.Name, _ = runMap["text"].(string)
+		owner.URL = parseBylineURL(map[string]interface{}{"runs": []interface{}{runMap}})
+		parseOwnerEndpoint(navEndpoint, owner)
+		info.Owner = owner
+		break
+	}
+}
+
+func parseAlbumItem(renderer map[string]interface{}) *PlaylistItem {
+	item := &PlaylistItem{}
+
+	if itemData, ok := renderer["playlistItemData"].(map[string]interface{}); ok {
+		item.ID, _ = itemData["videoId"].(string)
+		item.SetVideoID, _ = itemData["playlistSetVideoId"].(string)
+	}
+	if item.ID != "" {
+		item.URL = fmt.Sprintf("https://www.youtube.com/watch?v=%s", item.ID)
+	}
+
+	flexColumns, _ := renderer["flexColumns"].([]interface{})
+	if len(flexColumns) > 0 {
+		item.Title = parseText(albumColumnText(flexColumns[0], "musicResponsiveListItemFlexColumnRenderer"))
+	}
+	if len(flexColumns) > 1 {
+		if byline, ok := albumColumnText(flexColumns[1], "musicResponsiveListItemFlexColumnRenderer").(map[string]interface{}); ok {
+			item.Author = parseText(byline)
+			item.AuthorURL = parseBylineURL(byline)
+		}
+	}
+
+	fixedColumns, _ := renderer["fixedColumns"].([]interface{})
+	if len(fixedColumns) > 0 {
+		item.Duration = parseText(albumColumnText(fixedColumns[0], "musicResponsiveListItemFixedColumnRenderer"))
+		item.DurationSeconds, _ = textnum.ParseDuration(item.Duration)
+	}
+
+	if thumbnails := findRenderer(renderer["thumbnail"], "thumbnail"); thumbnails != nil {
+		if thumbnailList, ok := thumbnails["thumbnails"].([]interface{}); ok {
+			item.Thumbnails = parseThumbnails(thumbnailList)
+			if len(item.Thumbnails) > 0 {
+				item.Thumbnail = item.Thumbnails[0].URL
+			}
+		}
+	}
+
+	if index, ok := textnum.Parse(parseText(renderer["index"])); ok {
+		item.Index = index
+	}
+
+	policy, _ := renderer["musicItemRendererDisplayPolicy"].(string)
+	item.Unavailable = item.ID == "" || strings.HasSuffix(policy, "GREY_OUT")
+
+	return item
+}
+
+func albumColumnText(column interface{}, key string) interface{} {
+	columnMap, _ := column.(map[string]interface{})
+	renderer, _ := columnMap[key].(map[string]interface{})
+	return renderer["text"]
+}
+
+func albumContinuation(apiKey string, token string, context Context, opts *Options, stats *ParseStats) ([]PlaylistItem, string, error) {
+	payload := map[string]interface{}{
+		"context":      postContext(context, opts),
+		"continuation": token,
+	}
+
+	jsonResp, err := doPost(musicAPIURL(apiKey), opts, payload, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var rawTrackList []interface{}
+	var nextToken string
+	if continuation, ok := jsonResp["continuationContents"].(map[string]interface{}); ok {
+		shelf, _ := continuation["musicPlaylistShelfContinuation"].(map[string]interface{})
+		rawTrackList, _ = shelf["contents"].([]interface{})
+		nextToken = shelfContinuation(shelf)
+	} else if appendAction := findRenderer(jsonResp["onResponseReceivedActions"], "appendContinuationItemsAction"); appendAction != nil {
+		rawTrackList, _ = appendAction["continuationItems"].([]interface{})
+		nextToken = findContinuationToken(rawTrackList)
+	}
+
+	parsedItems, err := parseItems(rawTrackList, opts, stats)
+	if err != nil {
+		return parsedItems, "", err
+	}
+
+	opts.remaining -= len(parsedItems)
+
+	return parsedItems, pageToken(nextToken, opts), nil
+}
+
+func shelfContinuation(shelf map[string]interface{}) string {
+	if continuations, ok := shelf["continuations"].([]interface{}); ok && len(continuations) > 0 {
+		continuation, _ := continuations[0].(map[string]interface{})
+		data, _ := continuation["nextContinuationData"].(map[string]interface{})
+		if token, _ := data["continuation"].(string); IsPlausibleToken(token) {
+			return token
+		}
+	}
+
+	contents, _ := shelf["contents"].([]interface{})
+	return findContinuationToken(contents)
+}
+
+func findRenderer(obj interface{}, key string) map[string]interface{} {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if renderer, ok := v[key].(map[string]interface{}); ok {
+			return renderer
+		}
+		for _, value := range v {
+			if renderer := findRenderer(value, key); renderer != nil {
+				return renderer
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if renderer := findRenderer(value, key); renderer != nil {
+				return renderer
+			}
+		}
+	}
+	return nil
+}
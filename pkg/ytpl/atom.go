@@ -0,0 +1,83 @@
+package ytpl
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+}
+
+func (info *PlaylistInfo) WriteAtom(w io.Writer) error {
+	now := time.Now().UTC()
+	updated := now
+	if info.LastUpdatedAt != nil {
+		updated = info.LastUpdatedAt.UTC()
+	}
+
+	feed := atomFeed{
+		ID:      "yt:playlist:" + info.ID,
+		Title:   info.Title,
+		Updated: updated.Format(time.RFC3339),
+		Link:    atomLink{Href: info.URL, Rel: "alternate"},
+		Author:  atomAuthor{Name: "YouTube"},
+	}
+	if info.Owner != nil && info.Owner.Name != "" {
+		feed.Author = atomAuthor{Name: info.Owner.Name, URI: info.Owner.URL}
+	}
+
+	for _, item := range info.Items {
+		entryUpdated := updated
+		if item.UploadedAtTime != nil {
+			entryUpdated = item.UploadedAtTime.UTC()
+		}
+
+		entry := atomEntry{
+			ID:      "yt:video:" + item.ID,
+			Title:   item.Title,
+			Updated: entryUpdated.Format(time.RFC3339),
+			Link:    atomLink{Href: item.URL, Rel: "alternate"},
+		}
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author, URI: item.AuthorURL}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
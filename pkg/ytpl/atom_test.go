@@ -0,0 +1,93 @@
+package ytpl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"testing"
+	"time"
+)
+
+func atomFixture() *PlaylistInfo {
+	updated := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	uploaded := time.Date(2024, 5, 20, 8, 30, 0, 0, time.UTC)
+
+	return &PlaylistInfo{
+		ID:            "PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP",
+		Title:         "Rock & Roll <Live>",
+		URL:           "https://www.youtube.com/playlist?list=PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP",
+		LastUpdatedAt: &updated,
+		Owner:         &Owner{Name: "Tom & Jerry", URL: "https://www.youtube.com/@tomandjerry"},
+		Items: []PlaylistItem{
+			{
+				ID:             "dQw4w9WgXcQ",
+				Title:          "Salt & Pepper <Official Video>",
+				URL:            "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP",
+				Author:         "Salt & Pepper",
+				AuthorURL:      "https://www.youtube.com/@saltnpepper",
+				UploadedAtTime: &uploaded,
+			},
+			{
+				ID:    "9bZkp7q19f0",
+				Title: "Untitled",
+				URL:   "https://www.youtube.com/watch?v=9bZkp7q19f0",
+			},
+		},
+	}
+}
+
+func TestWriteAtomGolden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := atomFixture().WriteAtom(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile("testdata/playlist.atom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("WriteAtom() output differs from testdata/playlist.atom:\n%s", buf.String())
+	}
+}
+
+func TestWriteAtomRoundTrip(t *testing.T) {
+	info := atomFixture()
+
+	var buf bytes.Buffer
+	if err := info.WriteAtom(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+
+	if feed.Title != info.Title {
+		t.Errorf("feed title = %q, want %q", feed.Title, info.Title)
+	}
+	if feed.Author.Name != info.Owner.Name {
+		t.Errorf("feed author = %q, want %q", feed.Author.Name, info.Owner.Name)
+	}
+	if feed.Updated != "2024-06-01T12:00:00Z" {
+		t.Errorf("feed updated = %q", feed.Updated)
+	}
+	if len(feed.Entries) != len(info.Items) {
+		t.Fatalf("got %d entries, want %d", len(feed.Entries), len(info.Items))
+	}
+
+	first := feed.Entries[0]
+	if first.Title != info.Items[0].Title || first.Link.Href != info.Items[0].URL {
+		t.Errorf("first entry = %+v", first)
+	}
+	if first.Author == nil || first.Author.Name != info.Items[0].Author {
+		t.Errorf("first entry author = %+v", first.Author)
+	}
+	if first.Updated != "2024-05-20T08:30:00Z" {
+		t.Errorf("first entry updated = %q", first.Updated)
+	}
+	if second := feed.Entries[1]; second.Author != nil || second.Updated != feed.Updated {
+		t.Errorf("second entry = %+v", second)
+	}
+}
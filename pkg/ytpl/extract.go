@@ -0,0 +1,149 @@
+package ytpl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type jsonPath map[string]jsonPath
+
+var playlistPaths = jsonPath{
+	"error":   nil,
+	"alerts":  nil,
+	"sidebar": nil,
+	"contents": jsonPath{
+		"twoColumnBrowseResultsRenderer": jsonPath{
+			"tabs": jsonPath{
+				"tabRenderer": jsonPath{
+					"content": jsonPath{
+						"sectionListRenderer": jsonPath{
+							"contents": jsonPath{
+								"itemSectionRenderer": jsonPath{
+									"contents": jsonPath{
+										"playlistVideoListRenderer": nil,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+var continuationPaths = jsonPath{
+	"error": nil,
+	"onResponseReceivedActions": jsonPath{
+		"appendContinuationItemsAction": jsonPath{
+			"continuationItems": nil,
+		},
+	},
+}
+
+func fastPaths(opts *Options, paths jsonPath) jsonPath {
+	if opts.FastParse {
+		return paths
+	}
+	return nil
+}
+
+func extractPaths(data []byte, paths jsonPath) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	value, err := decodePaths(dec, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	return result, nil
+}
+
+func decodePaths(dec *json.Decoder, paths jsonPath) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := make(map[string]interface{})
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyTok.(string)
+
+			sub, wanted := paths[key]
+			switch {
+			case !wanted:
+				if err := skipValue(dec); err != nil {
+					return nil, err
+				}
+			case sub == nil:
+				var value interface{}
+				if err := dec.Decode(&value); err != nil {
+					return nil, err
+				}
+				obj[key] = value
+			default:
+				value, err := decodePaths(dec, sub)
+				if err != nil {
+					return nil, err
+				}
+				obj[key] = value
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case '[':
+		arr := []interface{}{}
+		for dec.More() {
+			value, err := decodePaths(dec, paths)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	}
+
+	return nil, fmt.Errorf("unexpected delimiter %q", delim)
+}
+
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
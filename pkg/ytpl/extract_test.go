@@ -0,0 +1,100 @@
+package ytpl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readFixture(tb testing.TB, name string) []byte {
+	tb.Helper()
+
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return data
+}
+
+func initialData(tb testing.TB, page []byte) []byte {
+	tb.Helper()
+
+	body := string(page)
+	start := strings.Index(body, "var ytInitialData = ") + len("var ytInitialData = ")
+	end := strings.Index(body[start:], ";</script>")
+	return []byte(body[start : start+end])
+}
+
+func TestContinuationFastParse(t *testing.T) {
+	body := readFixture(t, "continuation.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/youtubei/v1/browse" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	context := Context{}
+	context.Client.ClientName = "WEB"
+	context.Client.ClientVersion = "2.20240606.06.00"
+
+	token := "4qmFsgKCARIkVkxQTGJwaTZaYWhCOEZIZlNmdlRyVEFsU0Zha2R3c1ZRNUdQGgZDR1FRQVEiSUNHTUpFZ0ZhYVBvZ2dNRkRBUVFBUkFRUVJRdUNnUk9Lb1kwTWdZSUFoQUFHQUVnQVNvTUNBSVFBQ0FCS0FFd0FCZ0JLQUE"
+	genericItems, genericToken, err := GetPlaylistContinuation(token, "key", context, &Options{Limit: 100, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fastItems, fastToken, err := GetPlaylistContinuation(token, "key", context, &Options{Limit: 100, APIHost: server.URL, FastParse: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(genericItems) != 100 || genericItems[0].Index != 101 {
+		t.Fatalf("got %d items starting at %+v", len(genericItems), genericItems[0])
+	}
+	if !reflect.DeepEqual(fastItems, genericItems) {
+		t.Error("FastParse continuation items differ from the generic path")
+	}
+	if fastToken != genericToken || fastToken == "" {
+		t.Errorf("continuation token = %q, want %q", fastToken, genericToken)
+	}
+}
+
+func BenchmarkDecodePlaylist(b *testing.B) {
+	data := initialData(b, readFixture(b, "playlist_100.html"))
+	benchmarkDecode(b, data, playlistPaths)
+}
+
+func BenchmarkDecodeContinuation(b *testing.B) {
+	data := readFixture(b, "continuation.json")
+	benchmarkDecode(b, data, continuationPaths)
+}
+
+func benchmarkDecode(b *testing.B, data []byte, paths jsonPath) {
+	opts := &Options{}
+
+	b.Run("generic", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeInitialData(opts, data, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("targeted", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeInitialData(opts, data, paths); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -3,17 +3,29 @@ package ytpl
 import (
 	"errors"
 	"fmt"
-	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	BasePlistURL = "https://www.youtube.com/playlist?"
-	BaseAPIURL   = "https://www.youtube.com/youtubei/v1/browse?key="
+	BasePlistURL     = "https://www.youtube.com/playlist?"
+	BaseWatchURL     = "https://www.youtube.com/watch?"
+	BaseAPIURL       = "https://www.youtube.com/youtubei/v1/browse?key="
+	ConsentCookie    = "SOCS=CAI"
+	SafetyModeCookie = "PREF=f2=8000000"
+	DefaultAPIHost   = "www.youtube.com"
+	UserAgent        = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"
+	NoRetries        = -1
+
+	defaultRetries     = 3
+	defaultLimit       = 100
+	defaultConcurrency = 4
+	maxCachedChannels  = 256
 )
 
 var (
@@ -21,22 +33,67 @@ var (
 	AlbumRegex         = regexp.MustCompile(`^OLAK5uy_[a-zA-Z0-9-_]{33}$`)
 	ChannelRegex       = regexp.MustCompile(`^UC[a-zA-Z0-9-_]{22,32}$`)
 	ChannelOnPageRegex = regexp.MustCompile(`channel_id=UC([\w-]{22,32})"`)
-	YTHosts            = []string{"www.youtube.com", "youtube.com", "music.youtube.com"}
+	ChannelTitleRegex  = regexp.MustCompile(`<meta property="og:title" content="([^"]*)">`)
+	ChannelAvatarRegex = regexp.MustCompile(`<meta property="og:image" content="([^"]*)">`)
+	HandleRegex        = regexp.MustCompile(`^@[\w.-]{3,30}$`)
+	MixRegex           = regexp.MustCompile(`^RD[\w-]{10,}$`)
+	VideoIDRegex       = regexp.MustCompile(`^[\w-]{11}$`)
+	VideoChannelRegex  = regexp.MustCompile(`"channelId":"UC([\w-]{22,32})"`)
+	PlayabilityRegex   = regexp.MustCompile(`"playabilityStatus":\{"status":"([A-Z_]+)"`)
+	YTHosts            = []string{"www.youtube.com", "youtube.com", "m.youtube.com", "music.youtube.com", "youtu.be"}
 )
 
+var (
+	ErrUnknownRenderer  = errors.New("unknown renderer")
+	ErrBudgetExceeded   = errors.New("download budget exceeded")
+	ErrMixNotSupported  = errors.New("mixes not supported")
+	ErrUnknownPlaylist  = errors.New("unknown playlist")
+	ErrEmptyPlaylist    = errors.New("empty playlist")
+	ErrParseFailure     = errors.New("failed to parse playlist")
+	ErrInvalidID        = errors.New("invalid playlist id")
+	ErrPrivatePlaylist  = errors.New("playlist is private")
+	ErrPlaylistNotFound = errors.New("playlist not found")
+	ErrInvalidAPIHost   = errors.New("invalid api host")
+	ErrVideoUnavailable = errors.New("video unavailable")
+	ErrUnauthorized     = errors.New("request rejected as unauthorized")
+)
+
+var cache = &Cache{}
+
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
 func GetPlaylistID(linkOrID string) (string, error) {
+	return getPlaylistID(linkOrID, checkArgs("", nil))
+}
+
+func ResolvePlaylistID(linkOrID string, options *Options) (string, error) {
+	opts := checkArgs("", options)
+	opts.AllowMixes = false
+	return getPlaylistID(linkOrID, opts)
+}
+
+func getPlaylistID(linkOrID string, opts *Options) (string, error) {
+	allowMixes := opts.AllowMixes
 	if linkOrID == "" {
-		return "", errors.New("the linkOrId has to be a non-empty string")
+		return "", fmt.Errorf("%w: the linkOrId has to be a non-empty string", ErrInvalidID)
 	}
 
 	if PlaylistRegex.MatchString(linkOrID) || AlbumRegex.MatchString(linkOrID) {
 		return linkOrID, nil
 	}
 
+	if allowMixes && MixRegex.MatchString(linkOrID) {
+		return linkOrID, nil
+	}
+
 	if ChannelRegex.MatchString(linkOrID) {
 		return "UU" + linkOrID[2:], nil
 	}
 
+	if HandleRegex.MatchString(linkOrID) {
+		return toChannelList(fmt.Sprintf("https://www.youtube.com/%s", linkOrID), opts)
+	}
+
 	parsed, err := url.Parse(linkOrID)
 	if err != nil {
 		return "", fmt.Errorf("invalid URL: %v", err)
@@ -50,7 +107,7 @@ func GetPlaylistID(linkOrID string) (string, error) {
 		}
 	}
 	if !validHost {
-		return "", errors.New("not a known youtube link")
+		return "", fmt.Errorf("%w: not a known youtube link", ErrInvalidID)
 	}
 
 	if parsed.Query().Has("list") {
@@ -58,15 +115,21 @@ func GetPlaylistID(linkOrID string) (string, error) {
 		if PlaylistRegex.MatchString(listParam) || AlbumRegex.MatchString(listParam) {
 			return listParam, nil
 		}
+		if allowMixes && MixRegex.MatchString(listParam) {
+			return listParam, nil
+		}
 		if strings.HasPrefix(listParam, "RD") {
-			return "", errors.New("mixes not supported")
+			return "", ErrMixNotSupported
 		}
-		return "", errors.New("invalid or unknown list query in url")
+		return "", fmt.Errorf("%w: invalid or unknown list query in url", ErrInvalidID)
 	}
 
 	pathParts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if HandleRegex.MatchString(pathParts[0]) {
+		return toChannelList(fmt.Sprintf("https://www.youtube.com/%s", pathParts[0]), opts)
+	}
 	if len(pathParts) < 2 {
-		return "", fmt.Errorf("unable to find a id in \"%s\"", linkOrID)
+		return "", fmt.Errorf("%w: unable to find a id in \"%s\"", ErrInvalidID, linkOrID)
 	}
 
 	maybeType := pathParts[len(pathParts)-2]
@@ -78,40 +141,113 @@ func GetPlaylistID(linkOrID string) (string, error) {
 			return "UU" + maybeID[2:], nil
 		}
 	case "user":
-		return toChannelList(fmt.Sprintf("https://www.youtube.com/user/%s", maybeID))
+		return toChannelList(fmt.Sprintf("https://www.youtube.com/user/%s", maybeID), opts)
 	case "c":
-		return toChannelList(fmt.Sprintf("https://www.youtube.com/c/%s", maybeID))
+		return toChannelList(fmt.Sprintf("https://www.youtube.com/c/%s", maybeID), opts)
 	}
 
-	return "", fmt.Errorf("unable to find a id in \"%s\"", linkOrID)
+	return "", fmt.Errorf("%w: unable to find a id in \"%s\"", ErrInvalidID, linkOrID)
 }
 
-func toChannelList(ref string) (string, error) {
-	resp, err := http.Get(ref)
-	if err != nil {
-		return "", err
+func toChannelList(ref string, opts *Options) (string, error) {
+	if channel := cachedChannel(ref); channel != nil {
+		return "UU" + channel.ChannelID[2:], nil
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := doGet(ref, opts)
 	if err != nil {
 		return "", err
 	}
 
-	matches := ChannelOnPageRegex.FindStringSubmatch(string(body))
+	matches := ChannelOnPageRegex.FindSubmatch(body)
 	if len(matches) > 1 {
-		return "UU" + matches[1], nil
+		saveChannel(ref, parseChannelPage(body, ref, "UC"+string(matches[1])))
+		return "UU" + string(matches[1]), nil
 	}
 
 	return "", fmt.Errorf("unable to resolve the ref: %s", ref)
 }
 
+func GetVideoUploadsID(linkOrID string, options *Options) (string, error) {
+	videoID, err := getVideoID(linkOrID)
+	if err != nil {
+		return "", err
+	}
+
+	opts := checkArgs("", options)
+	params := url.Values{}
+	params.Set("v", videoID)
+
+	body, err := doGet(BaseWatchURL+params.Encode(), opts)
+	if err != nil {
+		return "", err
+	}
+
+	if matches := PlayabilityRegex.FindSubmatch(body); len(matches) > 1 {
+		switch status := string(matches[1]); status {
+		case "OK":
+		case "LOGIN_REQUIRED", "AGE_CHECK_REQUIRED", "CONTENT_CHECK_REQUIRED":
+			return "", fmt.Errorf("%w: %s is age-restricted or requires sign in", ErrVideoUnavailable, videoID)
+		default:
+			return "", fmt.Errorf("%w: %s has playability status %s", ErrVideoUnavailable, videoID, status)
+		}
+	}
+
+	if matches := VideoChannelRegex.FindSubmatch(body); len(matches) > 1 {
+		return "UU" + string(matches[1]), nil
+	}
+	if matches := ChannelOnPageRegex.FindSubmatch(body); len(matches) > 1 {
+		return "UU" + string(matches[1]), nil
+	}
+
+	return "", fmt.Errorf("%w: unable to find the channel of %s", ErrVideoUnavailable, videoID)
+}
+
+func getVideoID(linkOrID string) (string, error) {
+	if VideoIDRegex.MatchString(linkOrID) {
+		return linkOrID, nil
+	}
+
+	parsed, err := url.Parse(linkOrID)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %v", err)
+	}
+
+	validHost := false
+	for _, host := range YTHosts {
+		if parsed.Host == host {
+			validHost = true
+			break
+		}
+	}
+	if !validHost {
+		return "", fmt.Errorf("%w: not a known youtube link", ErrInvalidID)
+	}
+
+	if v := parsed.Query().Get("v"); VideoIDRegex.MatchString(v) {
+		return v, nil
+	}
+
+	pathParts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if parsed.Host == "youtu.be" && VideoIDRegex.MatchString(pathParts[0]) {
+		return pathParts[0], nil
+	}
+	if len(pathParts) == 2 && VideoIDRegex.MatchString(pathParts[1]) {
+		switch pathParts[0] {
+		case "shorts", "embed", "live", "v":
+			return pathParts[1], nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: unable to find a video id in \"%s\"", ErrInvalidID, linkOrID)
+}
+
 func ValidateID(linkOrID string) bool {
 	if linkOrID == "" {
 		return false
 	}
 
-	if PlaylistRegex.MatchString(linkOrID) || AlbumRegex.MatchString(linkOrID) || ChannelRegex.MatchString(linkOrID) {
+	if PlaylistRegex.MatchString(linkOrID) || AlbumRegex.MatchString(linkOrID) || ChannelRegex.MatchString(linkOrID) || HandleRegex.MatchString(linkOrID) {
 		return true
 	}
 
@@ -143,6 +279,9 @@ func ValidateID(linkOrID string) bool {
 	}
 
 	pathParts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if HandleRegex.MatchString(pathParts[0]) {
+		return true
+	}
 	if len(pathParts) < 2 {
 		return false
 	}
@@ -160,97 +299,161 @@ func ValidateID(linkOrID string) bool {
 	return false
 }
 
-func GetPlaylist(linkOrID string, options *Options) (*PlaylistInfo, error) {
-	return getPlaylist(linkOrID, options, 3)
-}
-
-func getPlaylist(linkOrID string, options *Options, retries int) (*PlaylistInfo, error) {
-	plistID, err := GetPlaylistID(linkOrID)
+func CheckPlaylistExists(linkOrID string, options *Options) (bool, error) {
+	opts := checkArgs("", options)
+	plistID, err := getPlaylistID(linkOrID, opts)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-
-	opts := checkArgs(plistID, options)
+	opts.Query["list"] = plistID
 
 	params := url.Values{}
 	for k, v := range opts.Query {
 		params.Set(k, v)
 	}
-	refURL := BasePlistURL + params.Encode()
 
-	resp, err := opts.RequestOptions.Get(refURL)
+	body, err := doGet(BasePlistURL+params.Encode(), opts)
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+
+	parsed, err := parseBody(string(body), opts, playlistPaths)
+	if err != nil {
+		return false, err
+	}
+	applyCache(parsed)
+
+	if parsed.JSON == nil {
+		if parsed.APIKey == "" || parsed.Context.Client.ClientVersion == "" {
+			return false, rawBodyError(opts, body, fmt.Errorf("%w: missing api key or client version", ErrParseFailure))
+		}
+
+		payload := map[string]interface{}{
+			"context":  postContext(parsed.Context, opts),
+			"browseId": "VL" + plistID,
+		}
+
+		parsed.JSON, err = doPost(BaseAPIURL+parsed.APIKey, opts, payload, fastPaths(opts, playlistPaths))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := alertError(parsed.JSON); err != nil {
+		if errors.Is(err, ErrPlaylistNotFound) || errors.Is(err, ErrPrivatePlaylist) {
+			return false, nil
+		}
+		return false, err
 	}
-	defer resp.Body.Close()
+	if parsed.JSON["sidebar"] == nil {
+		return false, rawBodyError(opts, body, fmt.Errorf("%w: no playlist sidebar", ErrParseFailure))
+	}
+	return true, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+func GetPlaylist(linkOrID string, options *Options) (*PlaylistInfo, error) {
+	opts := checkArgs("", options)
+	info, err := getPlaylist(linkOrID, opts, retryCount(opts))
+
+	var dryErr *dryRunError
+	if errors.As(err, &dryErr) {
+		return &PlaylistInfo{DryRun: dryErr.request}, nil
+	}
+	return info, err
+}
+
+func getPlaylist(linkOrID string, opts *Options, retries int) (*PlaylistInfo, error) {
+	plistID, err := getPlaylistID(linkOrID, opts)
 	if err != nil {
 		return nil, err
 	}
+	opts.Query["list"] = plistID
+
+	if opts.AllowMixes && strings.HasPrefix(plistID, "RD") {
+		return getMix(plistID, opts)
+	}
+	if AlbumRegex.MatchString(plistID) {
+		return getAlbum(plistID, opts)
+	}
 
-	parsed, err := parseBody(string(body), opts)
+	parsed, err := browseCached(plistID, opts)
 	if err != nil {
 		return nil, err
 	}
+	fromCache := parsed != nil
+
+	var body []byte
+	if parsed == nil {
+		params := url.Values{}
+		for k, v := range opts.Query {
+			params.Set(k, v)
+		}
+		refURL := BasePlistURL + params.Encode()
+
+		body, err = doGet(refURL, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err = parseBody(string(body), opts, fastPaths(opts, playlistPaths))
+		if err != nil {
+			return nil, err
+		}
+		fromCache = applyCache(parsed)
+	}
 
 	if parsed.JSON == nil {
 		browseID := "VL" + plistID
 		if parsed.APIKey == "" || parsed.Context.Client.ClientVersion == "" {
-			return nil, errors.New("missing api key or client version")
+			return nil, rawBodyError(opts, body, fmt.Errorf("%w: missing api key or client version", ErrParseFailure))
 		}
 
 		payload := map[string]interface{}{
-			"context":  parsed.Context,
+			"context":  postContext(parsed.Context, opts),
 			"browseId": browseID,
 		}
 
-		apiResp, err := doPost(BaseAPIURL+parsed.APIKey, opts.RequestOptions, payload)
+		apiResp, err := doPost(BaseAPIURL+parsed.APIKey, opts, payload, fastPaths(opts, playlistPaths))
+		if errors.Is(err, ErrBudgetExceeded) {
+			return nil, err
+		}
+		if errors.Is(err, ErrUnauthorized) && fromCache {
+			clearCache()
+		}
 		if err == nil {
 			parsed.JSON = apiResp
 		}
 	}
 
-	if parsed.JSON["sidebar"] == nil {
-		return nil, errors.New("unknown Playlist")
-	}
-
 	if parsed.JSON == nil {
-		if retries == 0 {
-			logger(string(body))
-			return nil, errors.New("unsupported playlist")
+		if retries <= 0 {
+			logger(opts.DebugDumpDir, string(body))
+			return nil, rawBodyError(opts, body, fmt.Errorf("%w: unsupported playlist", ErrParseFailure))
 		}
-		return getPlaylist(linkOrID, opts, retries-1)
+		return retryPlaylist(linkOrID, opts, retries)
 	}
 
-	if alerts, ok := parsed.JSON["alerts"]; ok && parsed.JSON["contents"] == nil {
-		if alertsList, ok := alerts.([]interface{}); ok {
-			for _, alert := range alertsList {
-				if alertMap, ok := alert.(map[string]interface{}); ok {
-					if alertRenderer, ok := alertMap["alertRenderer"].(map[string]interface{}); ok {
-						if alertType, ok := alertRenderer["type"].(string); ok && alertType == "ERROR" {
-							errorText := parseText(alertRenderer["text"])
-							return nil, errors.New(errorText)
-						}
-					}
-				}
-			}
-		}
+	if err := alertError(parsed.JSON); err != nil {
+		return nil, err
+	}
+
+	if parsed.JSON["sidebar"] == nil {
+		return nil, rawBodyError(opts, body, ErrUnknownPlaylist)
 	}
 
 	sidebar, ok := parsed.JSON["sidebar"].(map[string]interface{})
 	if !ok {
-		return nil, errors.New("invalid sidebar structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid sidebar structure", ErrParseFailure))
 	}
 
 	playlistSidebar, ok := sidebar["playlistSidebarRenderer"].(map[string]interface{})
 	if !ok {
-		return nil, errors.New("invalid playlist sidebar structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid playlist sidebar structure", ErrParseFailure))
 	}
 
 	items, ok := playlistSidebar["items"].([]interface{})
 	if !ok {
-		return nil, errors.New("invalid items structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid items structure", ErrParseFailure))
 	}
 
 	var info map[string]interface{}
@@ -264,15 +467,23 @@ func getPlaylist(linkOrID string, options *Options, retries int) (*PlaylistInfo,
 	}
 
 	if info == nil {
-		return nil, errors.New("could not find playlist info")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: could not find playlist info", ErrParseFailure))
 	}
 
 	resp_info := &PlaylistInfo{
 		ID:  plistID,
 		URL: fmt.Sprintf("%slist=%s", BasePlistURL, plistID),
 	}
+	if opts.KeepRawJSON {
+		resp_info.Raw = parsed.JSON
+	}
 
 	resp_info.Title = parseText(info["title"])
+	resp_info.Owner = parseOwner(items)
+	if channel := resolvedChannel(plistID); channel != nil {
+		resp_info.Owner = mergeOwner(resp_info.Owner, channel)
+	}
+	resp_info.PartialDueToRegion = hasRegionNotice(parsed.JSON["alerts"])
 	resp_info.Description = parseText(info["description"])
 
 	if thumbnailRenderer, ok := info["thumbnailRenderer"].(map[string]interface{}); ok {
@@ -311,49 +522,57 @@ func getPlaylist(linkOrID string, options *Options, retries int) (*PlaylistInfo,
 
 	if stats, ok := info["stats"].([]interface{}); ok && len(stats) > 0 {
 		resp_info.TotalItems = parseNumFromText(stats[0])
-		if len(stats) >= 3 {
-			resp_info.Views = parseNumFromText(stats[1])
+		for _, stat := range stats[1:] {
+			text := parseText(stat)
+			if resp_info.Views == 0 && isViewCountText(text) {
+				resp_info.Views = parseNumFromText(stat)
+			} else if resp_info.LastUpdated == "" && strings.Contains(strings.ToLower(text), "updated") {
+				resp_info.LastUpdated = text
+				if updatedAt, ok := parseUpdatedDate(text, time.Now()); ok {
+					resp_info.LastUpdatedAt = &updatedAt
+				}
+			}
 		}
 	}
 
-	contents, ok := parsed.JSON["contents"].(map[string]interface{})
-	if !ok {
-		return nil, errors.New("invalid contents structure")
+	contents := parsed.JSON["contents"]
+	if contents == nil {
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid contents structure", ErrParseFailure))
 	}
 
-	twoColumnBrowse, ok := contents["twoColumnBrowseResultsRenderer"].(map[string]interface{})
+	twoColumnBrowse, ok := findContentsRenderer(contents, "twoColumnBrowseResultsRenderer")
 	if !ok {
-		return nil, errors.New("invalid two column browse structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid two column browse structure", ErrParseFailure))
 	}
 
 	tabs, ok := twoColumnBrowse["tabs"].([]interface{})
 	if !ok || len(tabs) == 0 {
-		return nil, errors.New("invalid tabs structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid tabs structure", ErrParseFailure))
 	}
 
 	firstTab, ok := tabs[0].(map[string]interface{})
 	if !ok {
-		return nil, errors.New("invalid first tab structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid first tab structure", ErrParseFailure))
 	}
 
 	tabRenderer, ok := firstTab["tabRenderer"].(map[string]interface{})
 	if !ok {
-		return nil, errors.New("invalid tab renderer structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid tab renderer structure", ErrParseFailure))
 	}
 
 	content, ok := tabRenderer["content"].(map[string]interface{})
 	if !ok {
-		return nil, errors.New("invalid tab content structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid tab content structure", ErrParseFailure))
 	}
 
 	sectionList, ok := content["sectionListRenderer"].(map[string]interface{})
 	if !ok {
-		return nil, errors.New("invalid section list structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid section list structure", ErrParseFailure))
 	}
 
 	sectionContents, ok := sectionList["contents"].([]interface{})
 	if !ok {
-		return nil, errors.New("invalid section contents structure")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid section contents structure", ErrParseFailure))
 	}
 
 	var itemSectionRenderer map[string]interface{}
@@ -367,12 +586,15 @@ func getPlaylist(linkOrID string, options *Options, retries int) (*PlaylistInfo,
 	}
 
 	if itemSectionRenderer == nil {
-		return nil, errors.New("empty playlist")
+		if shouldRetryEmpty(resp_info, opts, retries) {
+			return retryPlaylist(linkOrID, opts, retries)
+		}
+		return nil, ErrEmptyPlaylist
 	}
 
 	itemSectionContents, ok := itemSectionRenderer["contents"].([]interface{})
 	if !ok {
-		return nil, errors.New("invalid item section contents")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid item section contents", ErrParseFailure))
 	}
 
 	var playlistVideoListRenderer map[string]interface{}
@@ -386,66 +608,396 @@ func getPlaylist(linkOrID string, options *Options, retries int) (*PlaylistInfo,
 	}
 
 	if playlistVideoListRenderer == nil {
-		return nil, errors.New("empty playlist")
+		if shouldRetryEmpty(resp_info, opts, retries) {
+			return retryPlaylist(linkOrID, opts, retries)
+		}
+		return nil, ErrEmptyPlaylist
+	}
+
+	if listID, ok := playlistVideoListRenderer["playlistId"].(string); ok && listID != "" && listID != plistID {
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: got playlist %s instead of %s", ErrParseFailure, listID, plistID))
+	}
+
+	if isEditable, ok := playlistVideoListRenderer["isEditable"].(bool); ok {
+		resp_info.IsEditable = isEditable
+	}
+	if canReorder, ok := playlistVideoListRenderer["canReorder"].(bool); ok {
+		resp_info.CanReorder = canReorder
 	}
 
 	rawVideoList, ok := playlistVideoListRenderer["contents"].([]interface{})
 	if !ok {
-		return nil, errors.New("invalid video list")
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid video list", ErrParseFailure))
 	}
 
-	for i, rawVideo := range rawVideoList {
-		if i >= opts.Limit {
-			break
-		}
-		if item := parseItem(rawVideo); item != nil {
-			resp_info.Items = append(resp_info.Items, *item)
-		}
+	resp_info.Items, err = parseItems(rawVideoList, opts, &resp_info.Stats)
+	if err != nil {
+		return nil, err
 	}
 
-	opts.Limit -= len(resp_info.Items)
+	if len(resp_info.Items) == 0 && !opts.cutoffReached && shouldRetryEmpty(resp_info, opts, retries) {
+		return retryPlaylist(linkOrID, opts, retries)
+	}
 
-	var token string
-	for _, item := range rawVideoList {
-		if itemMap, ok := item.(map[string]interface{}); ok {
-			for key := range itemMap {
-				if key == "continuationItemRenderer" {
-					token = getContinuationToken(itemMap)
-					break
+	opts.remaining -= len(resp_info.Items)
+
+	resp_info.APIKey = parsed.APIKey
+	resp_info.Context = parsed.Context
+
+	token := pageToken(findContinuationToken(rawVideoList), opts)
+	if err := firstPage(resp_info, opts); err != nil {
+		resp_info.NextToken = token
+		resp_info.FetchedCount = len(resp_info.Items)
+		return resp_info, err
+	}
+	if token == "" || opts.remaining < 1 || opts.cutoffReached || opts.truncated || opts.singlePage {
+		resp_info.NextToken = token
+		resp_info.FetchedCount = len(resp_info.Items)
+		return resp_info, nil
+	}
+
+	nestedResp, nextToken, err := parsePage2(parsed.APIKey, token, parsed.Context, opts, &resp_info.Stats)
+	if errors.Is(err, ErrUnauthorized) && fromCache && retries > 0 {
+		clearCache()
+		return retryPlaylist(linkOrID, opts, retries)
+	}
+	resp_info.Items = append(resp_info.Items, nestedResp...)
+	resp_info.FetchedCount = len(resp_info.Items)
+	resp_info.NextToken = nextToken
+	if err != nil {
+		return resp_info, err
+	}
+
+	return resp_info, nil
+}
+
+func retryPlaylist(linkOrID string, opts *Options, retries int) (*PlaylistInfo, error) {
+	opts.remaining = limitOf(opts)
+	opts.cutoffReached = false
+	opts.truncated = false
+	opts.seen = nil
+	if err := waitRetry(opts, retryCount(opts)-retries+1); err != nil {
+		return nil, err
+	}
+	return getPlaylist(linkOrID, opts, retries-1)
+}
+
+func shouldRetryEmpty(info *PlaylistInfo, opts *Options, retries int) bool {
+	return opts.RetryOnEmpty && retries > 0 && info.TotalItems > 0 && info.Stats.SkippedUnknown == 0
+}
+
+func GetPlaylists(ids []string, options *Options) (map[string]*PlaylistInfo, map[string]error) {
+	base := checkArgs("", options)
+
+	concurrency := base.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	results := make(map[string]*PlaylistInfo)
+	errs := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	jobs := make(chan string)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				info, err := GetPlaylist(id, base)
+
+				mu.Lock()
+				if err != nil {
+					errs[id] = err
+				} else {
+					results[id] = info
 				}
+				mu.Unlock()
 			}
-			if token != "" {
-				break
-			}
+		}()
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			jobs <- id
 		}
 	}
+	close(jobs)
+	wg.Wait()
 
-	if token == "" || opts.Limit < 1 {
-		return resp_info, nil
+	return results, errs
+}
+
+func GetPlaylistIter(linkOrID string, options *Options) (*PlaylistIter, error) {
+	opts := checkArgs("", options)
+	opts.singlePage = true
+
+	info, err := getPlaylist(linkOrID, opts, retryCount(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	iter := &PlaylistIter{
+		Info:  info,
+		items: info.Items,
+		token: info.NextToken,
+		opts:  opts,
+	}
+	info.Items = nil
+	info.NextToken = ""
+	info.FetchedCount = 0
+
+	return iter, nil
+}
+
+func (it *PlaylistIter) Next() (*PlaylistItem, bool) {
+	for len(it.items) == 0 {
+		if it.err != nil || it.token == "" || it.opts.remaining < 1 || it.opts.cutoffReached || it.opts.truncated {
+			return nil, false
+		}
+
+		items, nextToken, err := parseContinuationPage(it.Info.APIKey, it.token, it.Info.Context, it.opts, &it.Info.Stats)
+		if err == nil {
+			err = notifyPage(it.opts, items)
+		}
+		it.items = items
+		it.token = nextToken
+		it.err = err
+	}
+
+	item := it.items[0]
+	it.items = it.items[1:]
+	it.Info.FetchedCount++
+	return &item, true
+}
+
+func (it *PlaylistIter) Err() error {
+	return it.err
+}
+
+func getMix(plistID string, opts *Options) (*PlaylistInfo, error) {
+	params := url.Values{}
+	for k, v := range opts.Query {
+		params.Set(k, v)
+	}
+	if params.Get("v") == "" && len(plistID) == 13 {
+		params.Set("v", plistID[2:])
 	}
+	refURL := BaseWatchURL + params.Encode()
 
-	nestedResp, err := parsePage2(parsed.APIKey, token, parsed.Context, opts)
+	body, err := doGet(refURL, opts)
 	if err != nil {
-		return resp_info, err
+		return nil, err
 	}
 
-	resp_info.Items = append(resp_info.Items, nestedResp...)
-	return resp_info, nil
+	parsed, err := parseBody(string(body), opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.JSON == nil {
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: unsupported mix", ErrParseFailure))
+	}
+
+	contents, _ := parsed.JSON["contents"].(map[string]interface{})
+	watchNext, _ := contents["twoColumnWatchNextResults"].(map[string]interface{})
+	playlistWrapper, _ := watchNext["playlist"].(map[string]interface{})
+	playlist, ok := playlistWrapper["playlist"].(map[string]interface{})
+	if !ok {
+		return nil, rawBodyError(opts, body, fmt.Errorf("%w: invalid mix structure", ErrParseFailure))
+	}
+
+	rawVideoList, ok := playlist["contents"].([]interface{})
+	if !ok || len(rawVideoList) == 0 {
+		return nil, ErrEmptyPlaylist
+	}
+
+	resp_info := &PlaylistInfo{
+		ID:      plistID,
+		URL:     fmt.Sprintf("%slist=%s", BasePlistURL, plistID),
+		Title:   parseText(playlist["title"]),
+		APIKey:  parsed.APIKey,
+		Context: parsed.Context,
+	}
+	if opts.KeepRawJSON {
+		resp_info.Raw = parsed.JSON
+	}
+
+	resp_info.Items, err = parseItems(rawVideoList, opts, &resp_info.Stats)
+	if err != nil {
+		return nil, err
+	}
+	resp_info.TotalItems = len(resp_info.Items)
+	resp_info.FetchedCount = len(resp_info.Items)
+
+	return resp_info, firstPage(resp_info, opts)
 }
 
-func checkArgs(plistID string, options *Options) *Options {
-	if options == nil {
-		options = &Options{}
+func GetPlaylistContinuation(token string, apiKey string, context Context, options *Options) ([]PlaylistItem, string, error) {
+	if token == "" {
+		return nil, "", errors.New("the continuation token has to be a non-empty string")
+	}
+	if (apiKey == "" && context.Client.ClientName != musicClientName) || context.Client.ClientVersion == "" {
+		return nil, "", errors.New("missing api key or client version")
+	}
+
+	if !IsPlausibleToken(token) {
+		return []PlaylistItem{}, "", nil
+	}
+
+	opts := checkArgs("", options)
+	return parseContinuationPage(apiKey, token, context, opts, &ParseStats{})
+}
+
+func (info *PlaylistInfo) LiveItems() []PlaylistItem {
+	var items []PlaylistItem
+	for _, item := range info.Items {
+		if item.IsLiveNow {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (info *PlaylistInfo) UpcomingItems() []PlaylistItem {
+	var items []PlaylistItem
+	for _, item := range info.Items {
+		if item.IsUpcoming {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func browseCached(plistID string, opts *Options) (*ParsedResponse, error) {
+	cache.mu.RLock()
+	apiKey, context := cache.APIKey, cache.Context
+	cache.mu.RUnlock()
+	if apiKey == "" || context.Client.ClientVersion == "" {
+		return nil, nil
+	}
+
+	payload := map[string]interface{}{
+		"context":  postContext(context, opts),
+		"browseId": "VL" + plistID,
+	}
+
+	apiResp, err := doPost(BaseAPIURL+apiKey, opts, payload, fastPaths(opts, playlistPaths))
+	var dryErr *dryRunError
+	if errors.Is(err, ErrBudgetExceeded) || errors.As(err, &dryErr) {
+		return nil, err
 	}
-	if options.Limit <= 0 {
-		options.Limit = 100
+	if errors.Is(err, ErrUnauthorized) || (err == nil && apiResp["error"] != nil) {
+		clearCache()
+		return nil, nil
 	}
-	if options.RequestOptions == nil {
-		options.RequestOptions = &http.Client{Timeout: 30 * time.Second}
+	if err != nil {
+		return nil, nil
+	}
+
+	return &ParsedResponse{
+		JSON:    apiResp,
+		APIKey:  apiKey,
+		Context: context,
+	}, nil
+}
+
+func applyCache(parsed *ParsedResponse) bool {
+	if parsed.APIKey != "" && parsed.Context.Client.ClientVersion != "" {
+		cache.mu.Lock()
+		cache.APIKey = parsed.APIKey
+		cache.Context = parsed.Context
+		cache.mu.Unlock()
+		return false
+	}
+
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	if cache.APIKey == "" || cache.Context.Client.ClientVersion == "" {
+		return false
+	}
+	parsed.APIKey = cache.APIKey
+	parsed.Context = cache.Context
+	return true
+}
+
+func cachedChannel(ref string) *Owner {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	return cache.channels[ref]
+}
+
+func saveChannel(ref string, channel *Owner) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.channels == nil {
+		cache.channels = make(map[string]*Owner)
+	}
+	if _, ok := cache.channels[ref]; !ok && len(cache.channels) >= maxCachedChannels {
+		for key := range cache.channels {
+			delete(cache.channels, key)
+			break
+		}
+	}
+	cache.channels[ref] = channel
+}
+
+func clearCache() {
+	cache.mu.Lock()
+	cache.APIKey = ""
+	cache.Context = Context{}
+	cache.channels = nil
+	cache.mu.Unlock()
+}
+
+func retryCount(options *Options) int {
+	if options == nil || options.Retries == 0 {
+		return defaultRetries
+	}
+	if options.Retries < 0 {
+		return 0
+	}
+	return options.Retries
+}
+
+func limitOf(opts *Options) int {
+	if opts.Limit <= 0 {
+		return math.MaxInt
+	}
+	return opts.Limit
+}
+
+func checkArgs(plistID string, options *Options) *Options {
+	opts := Options{Limit: defaultLimit}
+	if options != nil {
+		opts = *options
+	}
+
+	opts.remaining = limitOf(&opts)
+	opts.bytesRead = 0
+	opts.cutoffReached = false
+	opts.truncated = false
+	opts.singlePage = false
+	opts.seen = nil
+	opts.fetched = 0
+	opts.total = 0
+
+	if opts.RequestOptions == nil {
+		opts.RequestOptions = defaultClient
+		if opts.InsecureSkipVerify || opts.DisableHTTP2 {
+			opts.RequestOptions = transportClient(opts.InsecureSkipVerify, opts.DisableHTTP2)
+		}
+	}
+
+	query := make(map[string]string, len(opts.Query))
+	for k, v := range opts.Query {
+		query[k] = v
 	}
-	if options.Query == nil {
-		options.Query = make(map[string]string)
+	opts.Query = query
+	if plistID != "" {
+		opts.Query["list"] = plistID
 	}
-	options.Query["list"] = plistID
-	return options
+	return &opts
 }
@@ -0,0 +1,408 @@
+package ytpl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const fixturePlaylistID = "PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP"
+
+func newFixtureServer(t *testing.T, page []byte) (*httptest.Server, *int32) {
+	t.Helper()
+
+	continuation := readFixture(t, "continuation.json")
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/playlist" && r.URL.Query().Get("list") == fixturePlaylistID:
+			w.Write(page)
+		case r.Method == http.MethodPost && r.URL.Path == "/youtubei/v1/browse":
+			var payload map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&payload)
+			if payload["continuation"] != nil {
+				w.Write(continuation)
+			} else {
+				w.Write(initialData(t, page))
+			}
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(clearCache)
+	clearCache()
+
+	return server, &requests
+}
+
+func TestGetPlaylistLimit(t *testing.T) {
+	server, requests := newFixtureServer(t, readFixture(t, "playlist_100.html"))
+
+	info, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 5, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.TotalItems != 100 {
+		t.Errorf("TotalItems = %d, want 100", info.TotalItems)
+	}
+	if info.FetchedCount != 5 {
+		t.Errorf("FetchedCount = %d, want 5", info.FetchedCount)
+	}
+	if len(info.Items) != 5 {
+		t.Fatalf("got %d items, want 5", len(info.Items))
+	}
+	for i, item := range info.Items {
+		if item.Index != i+1 {
+			t.Errorf("item %d has index %d", i, item.Index)
+		}
+	}
+	if info.NextToken != "" {
+		t.Errorf("NextToken = %q, want none for a partly consumed page", info.NextToken)
+	}
+	if info.Title != "Fixture Playlist" {
+		t.Errorf("Title = %q", info.Title)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("made %d requests, want 1", n)
+	}
+}
+
+func TestGetPlaylistFastParse(t *testing.T) {
+	server, _ := newFixtureServer(t, readFixture(t, "playlist_100.html"))
+
+	generic, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 100, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clearCache()
+	fast, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 100, APIHost: server.URL, FastParse: true, KeepRawJSON: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := fast.Raw["microformat"]; ok {
+		t.Error("FastParse decoded a path outside playlistPaths")
+	}
+	if len(generic.Items) != 100 {
+		t.Fatalf("got %d items, want 100", len(generic.Items))
+	}
+	if !reflect.DeepEqual(fast.Items, generic.Items) {
+		t.Error("FastParse items differ from the generic path")
+	}
+	if fast.NextToken != generic.NextToken || fast.NextToken == "" {
+		t.Errorf("NextToken = %q, want %q", fast.NextToken, generic.NextToken)
+	}
+	if fast.TotalItems != generic.TotalItems || fast.TotalItems != 100 {
+		t.Errorf("TotalItems = %d, want %d", fast.TotalItems, generic.TotalItems)
+	}
+}
+
+func itemIDs(items []PlaylistItem) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestGetPlaylistLimitSkipsUnavailable(t *testing.T) {
+	page := string(readFixture(t, "playlist_100.html"))
+	for _, title := range []string{"Track 2", "Track 3"} {
+		page = strings.Replace(page, `{"text":"`+title+`"}`, `{"text":"[Deleted video]"}`, 1)
+	}
+	server, requests := newFixtureServer(t, []byte(page))
+
+	info, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 5, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"vid00000001", "vid00000004", "vid00000005", "vid00000006", "vid00000007"}
+	if got := itemIDs(info.Items); !reflect.DeepEqual(got, want) {
+		t.Errorf("got items %v, want %v", got, want)
+	}
+	if info.Stats.SkippedUnavailable != 2 {
+		t.Errorf("SkippedUnavailable = %d, want 2", info.Stats.SkippedUnavailable)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("made %d requests, want 1", n)
+	}
+}
+
+func TestGetPlaylistIter(t *testing.T) {
+	server, requests := newFixtureServer(t, readFixture(t, "playlist_100.html"))
+
+	iter, err := GetPlaylistIter(fixturePlaylistID, &Options{Limit: 150, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("made %d requests before iterating, want 1", n)
+	}
+
+	count := 0
+	for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+		count++
+		if item.Index != count {
+			t.Fatalf("item %d has index %d", count, item.Index)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 150 {
+		t.Errorf("yielded %d items, want 150", count)
+	}
+	if iter.Info.FetchedCount != 150 {
+		t.Errorf("FetchedCount = %d, want 150", iter.Info.FetchedCount)
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+}
+
+func TestGetPlaylistNextTokenAfterFullPage(t *testing.T) {
+	server, _ := newFixtureServer(t, readFixture(t, "playlist_100.html"))
+
+	info, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 100, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.NextToken == "" {
+		t.Fatal("NextToken is empty after a fully consumed page")
+	}
+
+	items, _, err := GetPlaylistContinuation(info.NextToken, info.APIKey, info.Context, &Options{Limit: 1, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Index != 101 {
+		t.Errorf("continuation resumed at %+v, want item 101", items)
+	}
+}
+
+func TestChannelCache(t *testing.T) {
+	clearCache()
+	t.Cleanup(clearCache)
+
+	for i := 0; i < maxCachedChannels+10; i++ {
+		saveChannel(fmt.Sprintf("https://www.youtube.com/@channel%d", i), &Owner{ChannelID: fmt.Sprintf("UC%022d", i)})
+	}
+	if n := len(cache.channels); n != maxCachedChannels {
+		t.Errorf("cached %d channels, want %d", n, maxCachedChannels)
+	}
+	last := fmt.Sprintf("https://www.youtube.com/@channel%d", maxCachedChannels+9)
+	if cachedChannel(last) == nil {
+		t.Error("the latest channel was not cached")
+	}
+
+	clearCache()
+	if cachedChannel(last) != nil {
+		t.Error("clearCache kept resolved channels")
+	}
+}
+
+func TestCheckPlaylistExists(t *testing.T) {
+	page := string(readFixture(t, "playlist_100.html"))
+	data := string(initialData(t, []byte(page)))
+	alert := func(text string) string {
+		return `{"alerts":[{"alertRenderer":{"type":"ERROR","text":{"simpleText":"` + text + `"}}}],` + data[1:]
+	}
+
+	tests := []struct {
+		name   string
+		page   string
+		exists bool
+		err    error
+	}{
+		{"exists", page, true, nil},
+		{"not found", strings.Replace(page, data, alert("The playlist does not exist."), 1), false, nil},
+		{"private", strings.Replace(page, data, alert("This playlist is private."), 1), false, nil},
+		{"other alert", strings.Replace(page, data, alert("Something went wrong."), 1), false, ErrUnknownPlaylist},
+		{"no sidebar", strings.Replace(page, `"sidebar":`, `"sidebarGone":`, 1), false, ErrParseFailure},
+	}
+
+	for _, tt := range tests {
+		server, _ := newFixtureServer(t, []byte(tt.page))
+
+		exists, err := CheckPlaylistExists(fixturePlaylistID, &Options{APIHost: server.URL})
+		if exists != tt.exists || !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+			t.Errorf("%s: got %v, %v; want %v, %v", tt.name, exists, err, tt.exists, tt.err)
+		}
+	}
+}
+
+func TestGetPlaylistDedupe(t *testing.T) {
+	page := strings.ReplaceAll(string(readFixture(t, "playlist_100.html")), "vid00000004", "vid00000003")
+	server, _ := newFixtureServer(t, []byte(page))
+
+	tests := []struct {
+		dedupe bool
+		want   []string
+	}{
+		{false, []string{"vid00000001", "vid00000002", "vid00000003", "vid00000003", "vid00000005"}},
+		{true, []string{"vid00000001", "vid00000002", "vid00000003", "vid00000005", "vid00000006"}},
+	}
+
+	for _, tt := range tests {
+		clearCache()
+		info, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 5, Dedupe: tt.dedupe, APIHost: server.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := itemIDs(info.Items); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Dedupe=%v: got %v, want %v", tt.dedupe, got, tt.want)
+		}
+		if tt.dedupe && info.NextToken != "" {
+			t.Errorf("Dedupe=%v: NextToken set for a partly consumed page", tt.dedupe)
+		}
+	}
+}
+
+func TestGetPlaylistStrictParsing(t *testing.T) {
+	page := strings.Replace(string(readFixture(t, "playlist_100.html")), `{"playlistVideoRenderer":{"videoId":"vid00000003"`, `{"adSlotRenderer":{"videoId":"vid00000003"`, 1)
+	server, _ := newFixtureServer(t, []byte(page))
+
+	info, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 99, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Items) != 99 || info.Stats.SkippedUnknown != 1 {
+		t.Errorf("got %d items and %d unknown renderers, want 99 and 1", len(info.Items), info.Stats.SkippedUnknown)
+	}
+
+	clearCache()
+	_, err = GetPlaylist(fixturePlaylistID, &Options{Limit: 99, APIHost: server.URL, StrictParsing: true})
+	if !errors.Is(err, ErrUnknownRenderer) || !strings.Contains(err.Error(), "adSlotRenderer") {
+		t.Errorf("got %v, want ErrUnknownRenderer naming adSlotRenderer", err)
+	}
+}
+
+func TestGetPlaylistParseStats(t *testing.T) {
+	page := string(readFixture(t, "playlist_100.html"))
+	page = strings.Replace(page, `{"text":"Track 10"}`, `{"text":"[Private video]"}`, 1)
+	page = strings.Replace(page, `{"text":"Track 20"}`, `{"text":"[Deleted video]"}`, 1)
+	page = strings.Replace(page, `{"playlistVideoRenderer":{"videoId":"vid00000030"`, `{"adSlotRenderer":{"videoId":"vid00000030"`, 1)
+	server, _ := newFixtureServer(t, []byte(page))
+
+	info, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 97, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ParseStats{Parsed: 97, SkippedUnknown: 1, SkippedUnavailable: 2}
+	if info.Stats != want {
+		t.Errorf("Stats = %+v, want %+v", info.Stats, want)
+	}
+	if len(info.Items) != 97 {
+		t.Errorf("got %d items, want 97", len(info.Items))
+	}
+}
+
+func TestGetPlaylistMaxTotalBytes(t *testing.T) {
+	page := readFixture(t, "playlist_100.html")
+	continuation := readFixture(t, "continuation.json")
+	server, requests := newFixtureServer(t, page)
+
+	budget := int64(len(page) + len(continuation)/2)
+	info, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 150, MaxTotalBytes: budget, APIHost: server.URL})
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("got %v, want ErrBudgetExceeded", err)
+	}
+	if info == nil || len(info.Items) != 100 {
+		t.Errorf("got %+v, want the 100 items of the first page", info)
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+
+	clearCache()
+	budget = int64(len(page) + len(continuation))
+	info, err = GetPlaylist(fixturePlaylistID, &Options{Limit: 150, MaxTotalBytes: budget, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Items) != 150 {
+		t.Errorf("got %d items, want 150", len(info.Items))
+	}
+}
+
+var viewsRegex = regexp.MustCompile(`"videoInfo":\{"runs":\[\{"text":"(\d+)000 views"\}\]\}`)
+
+func TestGetPlaylistStopBeforeDate(t *testing.T) {
+	newest := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	page := viewsRegex.ReplaceAllStringFunc(string(readFixture(t, "playlist_100.html")), func(match string) string {
+		n, _ := strconv.Atoi(viewsRegex.FindStringSubmatch(match)[1])
+		label := fmt.Sprintf("%d views • Streamed %s", n*1000, newest.AddDate(0, 0, -n).Format("Jan 2, 2006"))
+		return `"videoInfo":{"runs":[{"text":"` + label + `"}],"accessibility":{"accessibilityData":{"label":"` + label + `"}}}`
+	})
+	server, requests := newFixtureServer(t, []byte(page))
+
+	cutoff := newest.AddDate(0, 0, -10).Add(-12 * time.Hour)
+	info, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 150, StopBeforeDate: &cutoff, APIHost: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(info.Items) != 10 {
+		t.Fatalf("got %d items, want 10", len(info.Items))
+	}
+	for _, item := range info.Items {
+		if item.UploadedAtTime == nil || item.UploadedAtTime.Before(cutoff) {
+			t.Errorf("item %s uploaded at %v, before the cutoff", item.ID, item.UploadedAtTime)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("made %d requests, want 1", n)
+	}
+}
+
+func TestGetPlaylistRetryOnEmpty(t *testing.T) {
+	page := readFixture(t, "playlist_100.html")
+	empty := []byte(strings.Replace(string(page), `"itemSectionRenderer"`, `"messageRenderer"`, 1))
+
+	var gets int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && atomic.AddInt32(&gets, 1) == 1:
+			w.Write(empty)
+		case r.Method == http.MethodGet:
+			w.Write(page)
+		default:
+			w.Write(initialData(t, page))
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(clearCache)
+
+	clearCache()
+	if _, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 100, APIHost: server.URL}); !errors.Is(err, ErrEmptyPlaylist) {
+		t.Fatalf("without RetryOnEmpty: got %v, want ErrEmptyPlaylist", err)
+	}
+
+	clearCache()
+	atomic.StoreInt32(&gets, 0)
+	info, err := GetPlaylist(fixturePlaylistID, &Options{Limit: 100, APIHost: server.URL, RetryOnEmpty: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Items) != 100 {
+		t.Errorf("got %d items after retrying, want 100", len(info.Items))
+	}
+
+}
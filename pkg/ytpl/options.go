@@ -0,0 +1,67 @@
+package ytpl
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Option func(*Options)
+
+func NewOptions(opts ...Option) *Options {
+	options := &Options{Limit: defaultLimit}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+func WithLimit(limit int) Option {
+	return func(o *Options) {
+		o.Limit = limit
+	}
+}
+
+func WithQuery(key string, value string) Option {
+	return func(o *Options) {
+		if o.Query == nil {
+			o.Query = make(map[string]string)
+		}
+		o.Query[key] = value
+	}
+}
+
+func WithHeader(key string, value string) Option {
+	return func(o *Options) {
+		if o.Headers == nil {
+			o.Headers = make(map[string]string)
+		}
+		o.Headers[key] = value
+	}
+}
+
+func WithClient(client *http.Client) Option {
+	return func(o *Options) {
+		o.RequestOptions = client
+	}
+}
+
+func WithProxy(proxyURL *url.URL) Option {
+	return func(o *Options) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+		o.RequestOptions = &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		}
+	}
+}
+
+func WithRetries(retries int) Option {
+	return func(o *Options) {
+		if retries <= 0 {
+			retries = NoRetries
+		}
+		o.Retries = retries
+	}
+}
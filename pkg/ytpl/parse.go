@@ -3,9 +3,14 @@ package ytpl
 import (
 	"encoding/json"
 	"fmt"
+	"html"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/Victiniiiii/ytpl-ytsr-go/pkg/internal/textnum"
 )
 
 func parseText(textObj interface{}) string {
@@ -35,19 +40,109 @@ func parseText(textObj interface{}) string {
 	return ""
 }
 
-func parseNumFromText(textObj interface{}) int {
-	text := parseText(textObj)
-	if text == "" {
-		return 0
+var relativeTimeRegex = regexp.MustCompile(`(\d+)\s+(second|minute|hour|day|week|month|year)s?\s+ago`)
+
+func parseRelativeTime(text string, now time.Time) (time.Time, bool) {
+	match := relativeTimeRegex.FindStringSubmatch(strings.ToLower(text))
+	if len(match) < 3 {
+		return time.Time{}, false
+	}
+
+	n, err := strconv.Atoi(match[1])
+	if err != nil {
+		return time.Time{}, false
 	}
 
-	numStr := regexp.MustCompile(`[^\d,.]`).ReplaceAllString(text, "")
-	numStr = strings.ReplaceAll(numStr, ",", "")
+	switch match[2] {
+	case "second":
+		return now.Add(-time.Duration(n) * time.Second), true
+	case "minute":
+		return now.Add(-time.Duration(n) * time.Minute), true
+	case "hour":
+		return now.Add(-time.Duration(n) * time.Hour), true
+	case "day":
+		return now.AddDate(0, 0, -n), true
+	case "week":
+		return now.AddDate(0, 0, -7*n), true
+	case "month":
+		return now.AddDate(0, -n, 0), true
+	case "year":
+		return now.AddDate(-n, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+var updatedDateLayouts = []string{"Jan 2, 2006", "January 2, 2006", "2 Jan 2006", "2 January 2006", "2006-01-02"}
+
+var exactDateRegex = regexp.MustCompile(`\b([A-Z][a-z]{2,8} \d{1,2}, \d{4}|\d{1,2} [A-Z][a-z]{2,8} \d{4}|\d{4}-\d{2}-\d{2})\b`)
+
+func parseExactDate(renderer map[string]interface{}) (time.Time, bool) {
+	for _, key := range []string{"publishedTimeText", "videoInfo"} {
+		textObj, _ := renderer[key].(map[string]interface{})
+		accessibility, _ := textObj["accessibility"].(map[string]interface{})
+		accessibilityData, _ := accessibility["accessibilityData"].(map[string]interface{})
+		label, _ := accessibilityData["label"].(string)
 
-	if num, err := strconv.Atoi(numStr); err == nil {
-		return num
+		for _, match := range exactDateRegex.FindAllString(label, -1) {
+			for _, layout := range updatedDateLayouts {
+				if date, err := time.ParseInLocation(layout, match, time.UTC); err == nil {
+					return date, true
+				}
+			}
+		}
 	}
-	return 0
+	return time.Time{}, false
+}
+
+func parseUpdatedDate(text string, now time.Time) (time.Time, bool) {
+	lower := strings.ToLower(text)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch {
+	case strings.Contains(lower, "today"):
+		return today, true
+	case strings.Contains(lower, "yesterday"):
+		return today.AddDate(0, 0, -1), true
+	}
+
+	if updatedAt, ok := parseRelativeTime(text, now); ok {
+		return updatedAt, true
+	}
+
+	dateText := text
+	if i := strings.Index(lower, " on "); i != -1 {
+		dateText = text[i+len(" on "):]
+	}
+	dateText = strings.TrimSpace(dateText)
+
+	for _, layout := range updatedDateLayouts {
+		if updatedAt, err := time.ParseInLocation(layout, dateText, now.Location()); err == nil {
+			return updatedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseNumFromText(textObj interface{}) int {
+	num, _ := textnum.Parse(parseText(textObj))
+	return num
+}
+
+var viewCountWords = []string{"view", "vista", "visualiza", "vue", "aufrufe", "weergave", "görüntülenme", "просмотр", "visninger", "wyświetle"}
+
+func isViewCountText(text string) bool {
+	text = strings.ToLower(text)
+	for _, word := range viewCountWords {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+var unavailableTitles = map[string]bool{
+	"[Deleted video]": true,
+	"[Private video]": true,
 }
 
 func parseItem(rawItem interface{}) *PlaylistItem {
@@ -56,6 +151,10 @@ func parseItem(rawItem interface{}) *PlaylistItem {
 		return nil
 	}
 
+	if renderer, ok := itemMap["musicResponsiveListItemRenderer"].(map[string]interface{}); ok {
+		return parseAlbumItem(renderer)
+	}
+
 	var renderer map[string]interface{}
 	for key, value := range itemMap {
 		if strings.Contains(key, "VideoRenderer") {
@@ -76,13 +175,20 @@ func parseItem(rawItem interface{}) *PlaylistItem {
 	}
 
 	item.Title = parseText(renderer["title"])
+	item.Unavailable = item.ID == "" || unavailableTitles[item.Title]
+
+	if index, err := strconv.Atoi(strings.TrimSpace(parseText(renderer["index"]))); err == nil {
+		item.Index = index
+	}
+	if setVideoID, ok := renderer["setVideoId"].(string); ok {
+		item.SetVideoID = setVideoID
+	}
 
 	if thumbnails, ok := renderer["thumbnail"].(map[string]interface{}); ok {
-		if thumbnailList, ok := thumbnails["thumbnails"].([]interface{}); ok && len(thumbnailList) > 0 {
-			if thumb, ok := thumbnailList[0].(map[string]interface{}); ok {
-				if url, ok := thumb["url"].(string); ok {
-					item.Thumbnail = url
-				}
+		if thumbnailList, ok := thumbnails["thumbnails"].([]interface{}); ok {
+			item.Thumbnails = parseThumbnails(thumbnailList)
+			if len(item.Thumbnails) > 0 {
+				item.Thumbnail = item.Thumbnails[0].URL
 			}
 		}
 	}
@@ -90,19 +196,532 @@ func parseItem(rawItem interface{}) *PlaylistItem {
 	if lengthText, ok := renderer["lengthText"].(map[string]interface{}); ok {
 		item.Duration = parseText(lengthText)
 	}
+	if item.Duration == "" {
+		item.Duration = parseOverlayDuration(renderer)
+	}
 
+	switch lengthSeconds := renderer["lengthSeconds"].(type) {
+	case string:
+		item.DurationSeconds, _ = strconv.Atoi(lengthSeconds)
+	case float64:
+		item.DurationSeconds = int(lengthSeconds)
+	}
+	if item.DurationSeconds == 0 {
+		item.DurationSeconds, _ = textnum.ParseDuration(item.Duration)
+	}
+
+	var byline map[string]interface{}
 	if shortBylineText, ok := renderer["shortBylineText"].(map[string]interface{}); ok {
-		item.Author = parseText(shortBylineText)
+		byline = shortBylineText
 	} else if ownerText, ok := renderer["ownerText"].(map[string]interface{}); ok {
-		item.Author = parseText(ownerText)
+		byline = ownerText
 	} else if longBylineText, ok := renderer["longBylineText"].(map[string]interface{}); ok {
-		item.Author = parseText(longBylineText)
+		byline = longBylineText
+	}
+	if byline != nil {
+		item.Author = parseText(byline)
+		item.AuthorURL = parseBylineURL(byline)
+	}
+
+	if selected, ok := renderer["selected"].(bool); ok {
+		item.IsSelected = selected
+	}
+
+	parseLiveStatus(renderer, item)
+	item.CanRemove = hasRemoveEndpoint(renderer)
+
+	item.Views = parseViewCount(renderer)
+	item.UploadedAt = parseDateText(renderer)
+	if uploadedAt, ok := parseExactDate(renderer); ok {
+		item.UploadedAtTime = &uploadedAt
+	} else if uploadedAt, ok := parseRelativeTime(item.UploadedAt, time.Now()); ok {
+		item.UploadedAtTime = &uploadedAt
 	}
 
 	return item
 }
 
-func parseBody(body string, opts *Options) (*ParsedResponse, error) {
+func hasRemoveEndpoint(renderer map[string]interface{}) bool {
+	menu, _ := renderer["menu"].(map[string]interface{})
+	menuRenderer, _ := menu["menuRenderer"].(map[string]interface{})
+	menuItems, _ := menuRenderer["items"].([]interface{})
+	for _, menuItem := range menuItems {
+		menuItemMap, _ := menuItem.(map[string]interface{})
+		serviceItem, ok := menuItemMap["menuServiceItemRenderer"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		endpoint, _ := serviceItem["serviceEndpoint"].(map[string]interface{})
+		editEndpoint, _ := endpoint["playlistEditEndpoint"].(map[string]interface{})
+		actions, _ := editEndpoint["actions"].([]interface{})
+		for _, action := range actions {
+			actionMap, _ := action.(map[string]interface{})
+			if name, _ := actionMap["action"].(string); name == "ACTION_REMOVE_VIDEO" || name == "ACTION_REMOVE_VIDEO_BY_VIDEO_ID" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseLiveStatus(renderer map[string]interface{}, item *PlaylistItem) {
+	if _, ok := renderer["upcomingEventData"].(map[string]interface{}); ok {
+		item.IsUpcoming = true
+	}
+
+	if overlays, ok := renderer["thumbnailOverlays"].([]interface{}); ok {
+		for _, overlay := range overlays {
+			overlayMap, _ := overlay.(map[string]interface{})
+			timeStatus, ok := overlayMap["thumbnailOverlayTimeStatusRenderer"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			style, _ := timeStatus["style"].(string)
+			text := strings.ToUpper(parseText(timeStatus["text"]))
+			switch style {
+			case "LIVE":
+				item.IsLiveNow = true
+			case "UPCOMING":
+				item.IsUpcoming = true
+			}
+			if strings.Contains(text, "PREMIERE") {
+				item.IsPremiere = true
+			}
+		}
+	}
+
+	if badges, ok := renderer["badges"].([]interface{}); ok {
+		for _, badge := range badges {
+			badgeMap, _ := badge.(map[string]interface{})
+			metadataBadge, ok := badgeMap["metadataBadgeRenderer"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			style, _ := metadataBadge["style"].(string)
+			label, _ := metadataBadge["label"].(string)
+			if style == "BADGE_STYLE_TYPE_LIVE_NOW" || label == "LIVE" || label == "LIVE NOW" {
+				item.IsLiveNow = true
+			}
+			if style == "BADGE_STYLE_TYPE_MEMBERS_ONLY" || strings.EqualFold(label, "Members only") {
+				item.IsMembersOnly = true
+			}
+		}
+	}
+
+	if item.IsLiveNow {
+		item.IsUpcoming = false
+	}
+}
+
+func parseThumbnails(thumbnailList []interface{}) []Thumbnail {
+	var thumbnails []Thumbnail
+	for _, thumb := range thumbnailList {
+		if thumbMap, ok := thumb.(map[string]interface{}); ok {
+			thumbnail := Thumbnail{}
+			thumbnail.URL, _ = thumbMap["url"].(string)
+			if width, ok := thumbMap["width"].(float64); ok {
+				thumbnail.Width = int(width)
+			}
+			if height, ok := thumbMap["height"].(float64); ok {
+				thumbnail.Height = int(height)
+			}
+			if thumbnail.URL != "" {
+				thumbnails = append(thumbnails, thumbnail)
+			}
+		}
+	}
+
+	sort.SliceStable(thumbnails, func(i, j int) bool {
+		if thumbnails[i].Width != thumbnails[j].Width {
+			return thumbnails[i].Width > thumbnails[j].Width
+		}
+		return thumbnails[i].Height > thumbnails[j].Height
+	})
+
+	return thumbnails
+}
+
+func parseViewCount(renderer map[string]interface{}) *int {
+	texts := []string{parseText(renderer["viewCountText"])}
+	if videoInfo, ok := renderer["videoInfo"].(map[string]interface{}); ok {
+		if runs, ok := videoInfo["runs"].([]interface{}); ok {
+			for _, run := range runs {
+				if runMap, ok := run.(map[string]interface{}); ok {
+					if text, ok := runMap["text"].(string); ok {
+						texts = append(texts, text)
+					}
+				}
+			}
+		}
+	}
+
+	for _, text := range texts {
+		if !isViewCountText(text) {
+			continue
+		}
+		if views, ok := textnum.Parse(text); ok {
+			return &views
+		}
+	}
+	return nil
+}
+
+func parseDateText(renderer map[string]interface{}) string {
+	if text := parseText(renderer["publishedTimeText"]); text != "" {
+		return text
+	}
+
+	if videoInfo, ok := renderer["videoInfo"].(map[string]interface{}); ok {
+		if runs, ok := videoInfo["runs"].([]interface{}); ok {
+			for _, run := range runs {
+				if runMap, ok := run.(map[string]interface{}); ok {
+					if text, ok := runMap["text"].(string); ok && strings.Contains(text, "ago") {
+						return text
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+var noticeRenderers = []string{"alertRenderer", "alertWithButtonRenderer", "messageRenderer", "backgroundPromoRenderer"}
+
+func hasRegionNotice(obj interface{}) bool {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for _, key := range noticeRenderers {
+			if renderer, ok := v[key].(map[string]interface{}); ok {
+				for _, field := range []string{"text", "title", "bodyText"} {
+					text := strings.ToLower(parseText(renderer[field]))
+					if strings.Contains(text, "country") || strings.Contains(text, "region") {
+						return true
+					}
+				}
+			}
+		}
+		for _, value := range v {
+			if hasRegionNotice(value) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if hasRegionNotice(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func findContentsRenderer(contents interface{}, key string) (map[string]interface{}, bool) {
+	switch v := contents.(type) {
+	case map[string]interface{}:
+		renderer, ok := v[key].(map[string]interface{})
+		return renderer, ok
+	case []interface{}:
+		for _, item := range v {
+			if itemMap, ok := item.(map[string]interface{}); ok {
+				if renderer, ok := itemMap[key].(map[string]interface{}); ok {
+					return renderer, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+func alertError(jsonData map[string]interface{}) error {
+	alerts, ok := jsonData["alerts"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, alert := range alerts {
+		alertMap, ok := alert.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, key := range []string{"alertRenderer", "alertWithButtonRenderer"} {
+			alertRenderer, ok := alertMap[key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if alertType, ok := alertRenderer["type"].(string); !ok || alertType != "ERROR" {
+				continue
+			}
+
+			errorText := parseText(alertRenderer["text"])
+			lower := strings.ToLower(errorText)
+			switch {
+			case strings.Contains(lower, "private"):
+				return fmt.Errorf("%w: %s", ErrPrivatePlaylist, errorText)
+			case strings.Contains(lower, "does not exist"), strings.Contains(lower, "doesn't exist"),
+				strings.Contains(lower, "deleted"), strings.Contains(lower, "unavailable"):
+				return fmt.Errorf("%w: %s", ErrPlaylistNotFound, errorText)
+			}
+			return fmt.Errorf("%w: %s", ErrUnknownPlaylist, errorText)
+		}
+	}
+	return nil
+}
+
+func parseOwner(sidebarItems []interface{}) *Owner {
+	var ownerRenderer map[string]interface{}
+	for _, item := range sidebarItems {
+		if itemMap, ok := item.(map[string]interface{}); ok {
+			if secondaryInfo, ok := itemMap["playlistSidebarSecondaryInfoRenderer"].(map[string]interface{}); ok {
+				if videoOwner, ok := secondaryInfo["videoOwner"].(map[string]interface{}); ok {
+					ownerRenderer, _ = videoOwner["videoOwnerRenderer"].(map[string]interface{})
+					break
+				}
+			}
+		}
+	}
+
+	if ownerRenderer == nil {
+		return nil
+	}
+
+	owner := &Owner{}
+
+	if title, ok := ownerRenderer["title"].(map[string]interface{}); ok {
+		owner.Name = parseText(title)
+		owner.URL = parseBylineURL(title)
+		if runs, ok := title["runs"].([]interface{}); ok && len(runs) > 0 {
+			run, _ := runs[0].(map[string]interface{})
+			navEndpoint, _ := run["navigationEndpoint"].(map[string]interface{})
+			parseOwnerEndpoint(navEndpoint, owner)
+		}
+	}
+
+	if owner.ChannelID == "" || owner.Handle == "" {
+		navEndpoint, _ := ownerRenderer["navigationEndpoint"].(map[string]interface{})
+		parseOwnerEndpoint(navEndpoint, owner)
+	}
+
+	if owner.Handle == "" {
+		if idx := strings.Index(owner.URL, "/@"); idx != -1 {
+			owner.Handle = owner.URL[idx+1:]
+		}
+	}
+
+	if badges, ok := ownerRenderer["badges"].([]interface{}); ok {
+		for _, badge := range badges {
+			if badgeMap, ok := badge.(map[string]interface{}); ok {
+				if renderer, ok := badgeMap["metadataBadgeRenderer"].(map[string]interface{}); ok {
+					tooltip, _ := renderer["tooltip"].(string)
+					style, _ := renderer["style"].(string)
+					if strings.Contains(strings.ToUpper(tooltip), "VERIFIED") ||
+						strings.Contains(strings.ToUpper(tooltip), "OFFICIAL") ||
+						strings.Contains(strings.ToUpper(tooltip), "ARTIST") ||
+						style == "BADGE_STYLE_TYPE_VERIFIED" || style == "BADGE_STYLE_TYPE_VERIFIED_ARTIST" {
+						owner.Verified = true
+					}
+				}
+			}
+		}
+	}
+
+	return owner
+}
+
+func parseChannelPage(body []byte, ref string, channelID string) *Owner {
+	owner := &Owner{
+		ChannelID: channelID,
+		URL:       "https://www.youtube.com/channel/" + channelID,
+	}
+
+	if matches := ChannelTitleRegex.FindSubmatch(body); len(matches) > 1 {
+		owner.Name = html.UnescapeString(string(matches[1]))
+	}
+	if matches := ChannelAvatarRegex.FindSubmatch(body); len(matches) > 1 {
+		owner.Avatar = &Thumbnail{URL: html.UnescapeString(string(matches[1]))}
+	}
+	if idx := strings.Index(ref, "/@"); idx != -1 && HandleRegex.MatchString(ref[idx+1:]) {
+		owner.Handle = ref[idx+1:]
+	}
+
+	return owner
+}
+
+func resolvedChannel(plistID string) *Owner {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	for _, channel := range cache.channels {
+		if "UU"+channel.ChannelID[2:] == plistID {
+			return channel
+		}
+	}
+	return nil
+}
+
+func mergeOwner(owner *Owner, channel *Owner) *Owner {
+	if owner == nil {
+		owner = &Owner{}
+	}
+
+	if owner.Name == "" {
+		owner.Name = channel.Name
+	}
+	if owner.ChannelID == "" {
+		owner.ChannelID = channel.ChannelID
+	}
+	if owner.Handle == "" {
+		owner.Handle = channel.Handle
+	}
+	if owner.URL == "" {
+		owner.URL = channel.URL
+	}
+	if owner.Avatar == nil && channel.Avatar != nil {
+		avatar := *channel.Avatar
+		owner.Avatar = &avatar
+	}
+	return owner
+}
+
+func parseOwnerEndpoint(navEndpoint map[string]interface{}, owner *Owner) {
+	browseEndpoint, ok := navEndpoint["browseEndpoint"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if browseID, ok := browseEndpoint["browseId"].(string); ok && owner.ChannelID == "" && ChannelRegex.MatchString(browseID) {
+		owner.ChannelID = browseID
+	}
+
+	if canonicalURL, ok := browseEndpoint["canonicalBaseUrl"].(string); ok && owner.Handle == "" {
+		if handle := strings.TrimPrefix(canonicalURL, "/"); HandleRegex.MatchString(handle) {
+			owner.Handle = handle
+		}
+	}
+}
+
+func parseOverlayDuration(renderer map[string]interface{}) string {
+	overlays, ok := renderer["thumbnailOverlays"].([]interface{})
+	if !ok {
+		return ""
+	}
+
+	for _, overlay := range overlays {
+		if overlayMap, ok := overlay.(map[string]interface{}); ok {
+			if timeStatus, ok := overlayMap["thumbnailOverlayTimeStatusRenderer"].(map[string]interface{}); ok {
+				if text := parseText(timeStatus["text"]); text != "" {
+					return text
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func parseBylineURL(byline map[string]interface{}) string {
+	runs, ok := byline["runs"].([]interface{})
+	if !ok || len(runs) == 0 {
+		return ""
+	}
+
+	run, _ := runs[0].(map[string]interface{})
+	navEndpoint, _ := run["navigationEndpoint"].(map[string]interface{})
+	browseEndpoint, _ := navEndpoint["browseEndpoint"].(map[string]interface{})
+	if canonicalURL, ok := browseEndpoint["canonicalBaseUrl"].(string); ok && canonicalURL != "" {
+		return "https://www.youtube.com" + canonicalURL
+	}
+	if browseID, ok := browseEndpoint["browseId"].(string); ok && browseID != "" {
+		return "https://www.youtube.com/channel/" + browseID
+	}
+	return ""
+}
+
+func parseItems(rawItems []interface{}, opts *Options, stats *ParseStats) ([]PlaylistItem, error) {
+	var items []PlaylistItem
+	opts.truncated = false
+	for i, rawItem := range rawItems {
+		if len(items) >= opts.remaining {
+			opts.truncated = hasMoreItems(rawItems[i:])
+			break
+		}
+
+		item := parseItem(rawItem)
+		if item != nil && item.Unavailable && !opts.IncludeUnavailable {
+			stats.SkippedUnavailable++
+			continue
+		}
+		if item != nil && opts.StopBeforeDate != nil && item.UploadedAtTime != nil && item.UploadedAtTime.Before(*opts.StopBeforeDate) {
+			opts.cutoffReached = true
+			break
+		}
+		if item != nil && opts.Dedupe && item.ID != "" {
+			if opts.seen[item.ID] {
+				continue
+			}
+			if opts.seen == nil {
+				opts.seen = make(map[string]bool)
+			}
+			opts.seen[item.ID] = true
+		}
+		if item != nil {
+			stats.Parsed++
+			items = append(items, *item)
+			continue
+		}
+
+		if key := unknownRendererKey(rawItem); key != "" {
+			stats.SkippedUnknown++
+			if opts.StrictParsing {
+				return items, fmt.Errorf("%w: %s", ErrUnknownRenderer, key)
+			}
+		} else if !isContinuationItem(rawItem) {
+			stats.SkippedUnavailable++
+		}
+	}
+	return items, nil
+}
+
+func pageToken(token string, opts *Options) string {
+	if opts.truncated {
+		return ""
+	}
+	return token
+}
+
+func hasMoreItems(rawItems []interface{}) bool {
+	for _, rawItem := range rawItems {
+		if !isContinuationItem(rawItem) {
+			return true
+		}
+	}
+	return false
+}
+
+func isContinuationItem(rawItem interface{}) bool {
+	itemMap, ok := rawItem.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = itemMap["continuationItemRenderer"]
+	return ok
+}
+
+func unknownRendererKey(rawItem interface{}) string {
+	itemMap, ok := rawItem.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	var unknown string
+	for key := range itemMap {
+		if strings.Contains(key, "VideoRenderer") || key == "musicResponsiveListItemRenderer" || key == "continuationItemRenderer" {
+			return ""
+		}
+		unknown = key
+	}
+	return unknown
+}
+
+func parseBody(body string, opts *Options, paths jsonPath) (*ParsedResponse, error) {
 	parsed := &ParsedResponse{}
 
 	apiKeyStart := strings.Index(body, `"INNERTUBE_API_KEY":"`)
@@ -124,13 +743,27 @@ func parseBody(body string, opts *Options) (*ParsedResponse, error) {
 		}
 	}
 
+	if parsed.APIKey == "" || parsed.Context.Client.ClientVersion == "" || parsed.Context.Client.VisitorData == "" {
+		applyYtcfg(parsed, extractYtcfg(body))
+	}
+
+	if opts != nil && opts.ExtractInitialData != nil {
+		if jsonStr, ok := opts.ExtractInitialData(body); ok {
+			if data, err := decodeInitialData(opts, []byte(jsonStr), paths); err == nil {
+				parsed.JSON = data
+				return parsed, nil
+			}
+		}
+	}
+
 	jsonStart := strings.Index(body, `var ytInitialData = `)
 	if jsonStart != -1 {
 		jsonStart += len(`var ytInitialData = `)
 		jsonEnd := strings.Index(body[jsonStart:], `;</script>`)
 		if jsonEnd != -1 {
 			jsonStr := body[jsonStart : jsonStart+jsonEnd]
-			if err := json.Unmarshal([]byte(jsonStr), &parsed.JSON); err == nil {
+			if data, err := decodeInitialData(opts, []byte(jsonStr), paths); err == nil {
+				parsed.JSON = data
 				return parsed, nil
 			}
 		}
@@ -139,73 +772,169 @@ func parseBody(body string, opts *Options) (*ParsedResponse, error) {
 	return parsed, nil
 }
 
-func parsePage2(apiKey string, token string, context Context, opts *Options) ([]PlaylistItem, error) {
+func extractYtcfg(body string) map[string]interface{} {
+	cfg := make(map[string]interface{})
+
+	for rest := body; ; {
+		start := strings.Index(rest, "ytcfg.set({")
+		if start == -1 {
+			break
+		}
+		rest = rest[start+len("ytcfg.set("):]
+
+		end := matchingBrace(rest)
+		if end == -1 {
+			break
+		}
+
+		var values map[string]interface{}
+		if err := json.Unmarshal([]byte(rest[:end+1]), &values); err == nil {
+			for k, v := range values {
+				cfg[k] = v
+			}
+		}
+		rest = rest[end+1:]
+	}
+
+	return cfg
+}
+
+func matchingBrace(s string) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func applyYtcfg(parsed *ParsedResponse, cfg map[string]interface{}) {
+	if parsed.APIKey == "" {
+		parsed.APIKey, _ = cfg["INNERTUBE_API_KEY"].(string)
+	}
+
+	if parsed.Context.Client.ClientVersion == "" {
+		if version, ok := cfg["INNERTUBE_CONTEXT_CLIENT_VERSION"].(string); ok && version != "" {
+			parsed.Context.Client.ClientVersion = version
+			parsed.Context.Client.ClientName = "WEB"
+		}
+	}
+
+	if parsed.Context.Client.VisitorData == "" {
+		if visitorData, ok := cfg["VISITOR_DATA"].(string); ok {
+			parsed.Context.Client.VisitorData = visitorData
+		} else if innertubeContext, ok := cfg["INNERTUBE_CONTEXT"].(map[string]interface{}); ok {
+			client, _ := innertubeContext["client"].(map[string]interface{})
+			parsed.Context.Client.VisitorData, _ = client["visitorData"].(string)
+		}
+	}
+}
+
+func decodeInitialData(opts *Options, data []byte, paths jsonPath) (map[string]interface{}, error) {
+	if paths != nil {
+		return extractPaths(data, paths)
+	}
+
+	var result map[string]interface{}
+	if err := unmarshalJSON(opts, data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func parsePage2(apiKey string, token string, context Context, opts *Options, stats *ParseStats) ([]PlaylistItem, string, error) {
+	parsedItems, nextToken, err := parseContinuationPage(apiKey, token, context, opts, stats)
+	if err != nil {
+		return parsedItems, token, err
+	}
+	if err := notifyPage(opts, parsedItems); err != nil {
+		return parsedItems, nextToken, err
+	}
+
+	if nextToken == "" || opts.remaining < 1 || opts.cutoffReached || opts.truncated {
+		return parsedItems, nextToken, nil
+	}
+
+	nestedResp, nestedToken, err := parsePage2(apiKey, nextToken, context, opts, stats)
+	parsedItems = append(parsedItems, nestedResp...)
+	return parsedItems, nestedToken, err
+}
+
+func firstPage(info *PlaylistInfo, opts *Options) error {
+	opts.fetched = 0
+	opts.total = info.TotalItems
+	return notifyPage(opts, info.Items)
+}
+
+func notifyPage(opts *Options, items []PlaylistItem) error {
+	opts.fetched += len(items)
+	if opts.OnPage == nil {
+		return nil
+	}
+	return opts.OnPage(items, opts.fetched, opts.total)
+}
+
+func parseContinuationPage(apiKey string, token string, context Context, opts *Options, stats *ParseStats) ([]PlaylistItem, string, error) {
+	if context.Client.ClientName == musicClientName {
+		return albumContinuation(apiKey, token, context, opts, stats)
+	}
+
 	payload := map[string]interface{}{
-		"context":      context,
+		"context":      postContext(context, opts),
 		"continuation": token,
 	}
 
-	jsonResp, err := doPost(BaseAPIURL+apiKey, opts.RequestOptions, payload)
+	jsonResp, err := doPost(BaseAPIURL+apiKey, opts, payload, fastPaths(opts, continuationPaths))
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	actions, ok := jsonResp["onResponseReceivedActions"].([]interface{})
 	if !ok || len(actions) == 0 {
-		return []PlaylistItem{}, nil
+		return []PlaylistItem{}, "", nil
 	}
 
 	action, ok := actions[0].(map[string]interface{})
 	if !ok {
-		return []PlaylistItem{}, nil
+		return []PlaylistItem{}, "", nil
 	}
 
 	appendAction, ok := action["appendContinuationItemsAction"].(map[string]interface{})
 	if !ok {
-		return []PlaylistItem{}, nil
+		return []PlaylistItem{}, "", nil
 	}
 
 	wrapper, ok := appendAction["continuationItems"].([]interface{})
 	if !ok {
-		return []PlaylistItem{}, nil
+		return []PlaylistItem{}, "", nil
 	}
 
-	var parsedItems []PlaylistItem
-	for i, item := range wrapper {
-		if i >= opts.Limit {
-			break
-		}
-		if parsedItem := parseItem(item); parsedItem != nil {
-			parsedItems = append(parsedItems, *parsedItem)
-		}
-	}
-
-	opts.Limit -= len(parsedItems)
-
-	var nextToken string
-	for _, item := range wrapper {
-		if itemMap, ok := item.(map[string]interface{}); ok {
-			for key := range itemMap {
-				if key == "continuationItemRenderer" {
-					nextToken = getContinuationToken(itemMap)
-					break
-				}
-			}
-			if nextToken != "" {
-				break
-			}
-		}
-	}
-
-	if nextToken == "" || opts.Limit < 1 {
-		return parsedItems, nil
-	}
-
-	nestedResp, err := parsePage2(apiKey, nextToken, context, opts)
+	parsedItems, err := parseItems(wrapper, opts, stats)
 	if err != nil {
-		return parsedItems, err
+		return parsedItems, "", err
 	}
 
-	parsedItems = append(parsedItems, nestedResp...)
-	return parsedItems, nil
+	opts.remaining -= len(parsedItems)
+
+	return parsedItems, pageToken(findContinuationToken(wrapper), opts), nil
 }
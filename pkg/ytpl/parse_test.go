@@ -0,0 +1,94 @@
+package ytpl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMergeOwnerCopiesAvatar(t *testing.T) {
+	channel := &Owner{
+		Name:      "Example Artist",
+		ChannelID: "UCexampleartist0000000000",
+		Avatar:    &Thumbnail{URL: "https://yt3.ggpht.com/example=s88", Width: 88, Height: 88},
+	}
+
+	for _, owner := range []*Owner{nil, {Name: "Example"}} {
+		merged := mergeOwner(owner, channel)
+		if merged.Avatar == nil || *merged.Avatar != *channel.Avatar {
+			t.Fatalf("merged avatar = %+v, want %+v", merged.Avatar, channel.Avatar)
+		}
+
+		merged.Avatar.URL = "changed"
+		if channel.Avatar.URL == "changed" {
+			t.Fatal("mutating the merged owner changed the cached channel")
+		}
+	}
+}
+
+func TestParseThumbnailsOrder(t *testing.T) {
+	var thumbnails []interface{}
+	if err := json.Unmarshal([]byte(`[
+		{"url":"https://i.ytimg.com/a.jpg","width":168,"height":94},
+		{"url":"https://i.ytimg.com/b.jpg","width":336,"height":188},
+		{"url":"https://i.ytimg.com/c.jpg","width":336,"height":200},
+		{"url":"https://i.ytimg.com/d.jpg","width":168,"height":94},
+		{"url":"https://i.ytimg.com/e.jpg","width":168,"height":110}
+	]`), &thumbnails); err != nil {
+		t.Fatal(err)
+	}
+
+	var urls []string
+	for _, thumbnail := range parseThumbnails(thumbnails) {
+		urls = append(urls, thumbnail.URL)
+	}
+	want := []string{
+		"https://i.ytimg.com/c.jpg",
+		"https://i.ytimg.com/b.jpg",
+		"https://i.ytimg.com/e.jpg",
+		"https://i.ytimg.com/a.jpg",
+		"https://i.ytimg.com/d.jpg",
+	}
+	if strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", urls, want)
+	}
+}
+
+func TestParseCompactVideoRenderer(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantSeconds int
+		wantLive    bool
+	}{
+		{"video", `{"compactVideoRenderer":{"videoId":"vid00000201","title":{"simpleText":"Compact track"},
+			"lengthText":{"simpleText":"1:02:03"},
+			"shortBylineText":{"runs":[{"text":"Compact Artist","navigationEndpoint":{"browseEndpoint":{"browseId":"UCexampleartist0000000000","canonicalBaseUrl":"/@compactartist"}}}]}}}`, 3723, false},
+		{"live", `{"compactVideoRenderer":{"videoId":"vid00000201","title":{"simpleText":"Compact track"},
+			"shortBylineText":{"runs":[{"text":"Compact Artist","navigationEndpoint":{"browseEndpoint":{"browseId":"UCexampleartist0000000000","canonicalBaseUrl":"/@compactartist"}}}]},
+			"thumbnailOverlays":[{"thumbnailOverlayTimeStatusRenderer":{"style":"LIVE","text":{"simpleText":"LIVE"}}}]}}`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw interface{}
+			if err := json.Unmarshal([]byte(tt.data), &raw); err != nil {
+				t.Fatal(err)
+			}
+
+			item := parseItem(raw)
+			if item == nil {
+				t.Fatal("compactVideoRenderer was skipped")
+			}
+			if item.ID != "vid00000201" || item.Title != "Compact track" || item.Unavailable {
+				t.Errorf("got ID %q Title %q Unavailable %v", item.ID, item.Title, item.Unavailable)
+			}
+			if item.Author != "Compact Artist" || item.AuthorURL != "https://www.youtube.com/@compactartist" {
+				t.Errorf("got Author %q AuthorURL %q", item.Author, item.AuthorURL)
+			}
+			if item.DurationSeconds != tt.wantSeconds || item.IsLiveNow != tt.wantLive {
+				t.Errorf("DurationSeconds = %d live %v, want %d live %v", item.DurationSeconds, item.IsLiveNow, tt.wantSeconds, tt.wantLive)
+			}
+		})
+	}
+}
@@ -1,18 +1,34 @@
 package ytpl
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
 
 type PlaylistItem struct {
-	ID         string `json:"id"`
-	Title      string `json:"title"`
-	URL        string `json:"url"`
-	Duration   string `json:"duration"`
-	Thumbnail  string `json:"thumbnail"`
-	Author     string `json:"author"`
-	AuthorURL  string `json:"author_url"`
-	IsLiveNow  bool   `json:"is_live_now"`
-	IsUpcoming bool   `json:"is_upcoming"`
-	IsPremiere bool   `json:"is_premiere"`
+	ID              string      `json:"id"`
+	Title           string      `json:"title"`
+	URL             string      `json:"url"`
+	Index           int         `json:"index"`
+	SetVideoID      string      `json:"set_video_id"`
+	Duration        string      `json:"duration"`
+	DurationSeconds int         `json:"duration_seconds"`
+	Thumbnail       string      `json:"thumbnail"`
+	Thumbnails      []Thumbnail `json:"thumbnails"`
+	Author          string      `json:"author"`
+	AuthorURL       string      `json:"author_url"`
+	Views           *int        `json:"views,omitempty"`
+	IsLiveNow       bool        `json:"is_live_now"`
+	IsUpcoming      bool        `json:"is_upcoming"`
+	IsPremiere      bool        `json:"is_premiere"`
+	IsSelected      bool        `json:"is_selected"`
+	IsMembersOnly   bool        `json:"is_members_only"`
+	CanRemove       bool        `json:"can_remove"`
+	Unavailable     bool        `json:"unavailable"`
+	UploadedAt      string      `json:"uploaded_at"`
+	UploadedAtTime  *time.Time  `json:"uploaded_at_time,omitempty"`
 }
 
 type Thumbnail struct {
@@ -21,28 +37,141 @@ type Thumbnail struct {
 	Height int    `json:"height"`
 }
 
+type Owner struct {
+	Name      string     `json:"name"`
+	ChannelID string     `json:"channel_id"`
+	Handle    string     `json:"handle"`
+	URL       string     `json:"url"`
+	Avatar    *Thumbnail `json:"avatar,omitempty"`
+	Verified  bool       `json:"verified"`
+}
+
 type PlaylistInfo struct {
-	ID          string         `json:"id"`
-	Thumbnail   Thumbnail      `json:"thumbnail"`
-	URL         string         `json:"url"`
-	Title       string         `json:"title"`
-	Description string         `json:"description"`
-	TotalItems  int            `json:"total_items"`
-	Views       int            `json:"views"`
-	Items       []PlaylistItem `json:"items"`
+	ID                 string                 `json:"id"`
+	Thumbnail          Thumbnail              `json:"thumbnail"`
+	URL                string                 `json:"url"`
+	Title              string                 `json:"title"`
+	Description        string                 `json:"description"`
+	TotalItems         int                    `json:"total_items"`
+	FetchedCount       int                    `json:"fetched_count"`
+	Views              int                    `json:"views"`
+	IsEditable         bool                   `json:"is_editable"`
+	CanReorder         bool                   `json:"can_reorder"`
+	PartialDueToRegion bool                   `json:"partial_due_to_region"`
+	LastUpdated        string                 `json:"last_updated"`
+	LastUpdatedAt      *time.Time             `json:"last_updated_at,omitempty"`
+	Owner              *Owner                 `json:"owner,omitempty"`
+	Items              []PlaylistItem         `json:"items"`
+	Stats              ParseStats             `json:"stats"`
+	NextToken          string                 `json:"next_token"`
+	APIKey             string                 `json:"api_key"`
+	Context            Context                `json:"context"`
+	Raw                map[string]interface{} `json:"raw,omitempty"`
+	DryRun             *DryRunRequest         `json:"dry_run,omitempty"`
+}
+
+type ParseStats struct {
+	Parsed             int `json:"parsed"`
+	SkippedUnknown     int `json:"skipped_unknown"`
+	SkippedUnavailable int `json:"skipped_unavailable"`
 }
 
 type Options struct {
-	Limit          int
-	RequestOptions *http.Client
-	Query          map[string]string
+	Limit              int
+	RequestOptions     *http.Client
+	Query              map[string]string
+	ExtractInitialData func(body string) (string, bool)
+	DebugDumpDir       string
+	StrictParsing      bool
+	Headers            map[string]string
+	AttachRawBody      bool
+	MaxTotalBytes      int64
+	StopBeforeDate     *time.Time
+	InsecureSkipVerify bool
+	DisableHTTP2       bool
+	AllowMixes         bool
+	UnmarshalJSON      func(data []byte, v interface{}) error
+	Retries            int // zero means the default of 3; NoRetries disables retrying
+	RetryOnEmpty       bool
+	RetryBackoff       time.Duration
+	ExponentialBackoff bool
+	RequestContext     context.Context
+	PerRequestTimeout  time.Duration
+	FastParse          bool
+	KeepRawJSON        bool
+	APIHost            string
+	SafeSearch         bool
+	DryRun             bool
+	Dedupe             bool
+	IncludeUnavailable bool
+	OnPage             func(items []PlaylistItem, fetched, total int) error
+	Concurrency        int
+
+	remaining     int
+	bytesRead     int64
+	cutoffReached bool
+	truncated     bool
+	singlePage    bool
+	seen          map[string]bool
+	fetched       int
+	total         int
+}
+
+type PlaylistIter struct {
+	Info *PlaylistInfo
+
+	items []PlaylistItem
+	token string
+	opts  *Options
+	err   error
+}
+
+type DryRunRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+type dryRunError struct {
+	request *DryRunRequest
+}
+
+func (e *dryRunError) Error() string {
+	return "dry run: " + e.request.Method + " " + e.request.URL
+}
+
+type RawBodyError struct {
+	Err       error
+	Body      []byte
+	Truncated bool
+}
+
+func (e *RawBodyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RawBodyError) Unwrap() error {
+	return e.Err
+}
+
+type Cache struct {
+	mu      sync.RWMutex
+	APIKey  string
+	Context Context
+
+	channels map[string]*Owner
 }
 
 type Context struct {
 	Client struct {
 		ClientName    string `json:"clientName"`
 		ClientVersion string `json:"clientVersion"`
+		VisitorData   string `json:"visitorData,omitempty"`
 	} `json:"client"`
+	User struct {
+		EnableSafetyMode bool `json:"enableSafetyMode,omitempty"`
+	} `json:"user"`
 }
 
 type ParsedResponse struct {
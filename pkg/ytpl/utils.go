@@ -1,21 +1,48 @@
 package ytpl
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-func logger(content string) {
-	dir := filepath.Join(".", "dumps")
+const (
+	MaxRawBodySize = 512 * 1024
+	minTokenLength = 12
+)
+
+func rawBodyError(opts *Options, body []byte, err error) error {
+	if !opts.AttachRawBody {
+		return err
+	}
+
+	rawErr := &RawBodyError{Err: err}
+	if len(body) > MaxRawBodySize {
+		body = body[:MaxRawBodySize]
+		rawErr.Truncated = true
+	}
+	rawErr.Body = append([]byte(nil), body...)
+	return rawErr
+}
+
+func logger(dir string, content string) {
+	if dir == "" {
+		return
+	}
+
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		log.Printf("Failed to create dumps directory: %v", err)
 		return
@@ -37,6 +64,30 @@ func logger(content string) {
 	log.Printf("%s\\", strings.Repeat("*", 200))
 }
 
+func IsPlausibleToken(token string) bool {
+	token = strings.TrimRight(strings.ReplaceAll(token, "%3D", "="), "=")
+	if len(token) < minTokenLength {
+		return false
+	}
+
+	token = strings.NewReplacer("+", "-", "/", "_").Replace(token)
+	_, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil
+}
+
+func findContinuationToken(items []interface{}) string {
+	for _, item := range items {
+		if itemMap, ok := item.(map[string]interface{}); ok {
+			if _, ok := itemMap["continuationItemRenderer"]; ok {
+				if token := getContinuationToken(itemMap); IsPlausibleToken(token) {
+					return token
+				}
+			}
+		}
+	}
+	return ""
+}
+
 func getContinuationToken(item map[string]interface{}) string {
 	if item == nil {
 		return ""
@@ -105,27 +156,244 @@ func findTokenRecursively(obj interface{}) string {
 	return ""
 }
 
-func doPost(url string, client *http.Client, payload interface{}) (map[string]interface{}, error) {
-	jsonData, err := json.Marshal(payload)
+func readBody(r io.Reader, opts *Options) ([]byte, error) {
+	if opts.MaxTotalBytes <= 0 {
+		return io.ReadAll(r)
+	}
+
+	remaining := opts.MaxTotalBytes - opts.bytesRead
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, remaining+1))
+	opts.bytesRead += int64(len(body))
 	if err != nil {
 		return nil, err
 	}
+	if opts.bytesRead > opts.MaxTotalBytes {
+		return nil, ErrBudgetExceeded
+	}
+	return body, nil
+}
 
-	resp, err := client.Post(url, "application/json", strings.NewReader(string(jsonData)))
+type transportKey struct {
+	insecureSkipVerify bool
+	disableHTTP2       bool
+}
+
+var (
+	transportClientsMu sync.Mutex
+	transportClients   = map[transportKey]*http.Client{}
+)
+
+func transportClient(insecureSkipVerify bool, disableHTTP2 bool) *http.Client {
+	transportClientsMu.Lock()
+	defer transportClientsMu.Unlock()
+
+	key := transportKey{insecureSkipVerify, disableHTTP2}
+	if client, ok := transportClients[key]; ok {
+		return client
+	}
+
+	client := &http.Client{
+		Timeout:   defaultClient.Timeout,
+		Transport: newTransport(insecureSkipVerify, disableHTTP2),
+	}
+	transportClients[key] = client
+	return client
+}
+
+func newTransport(insecureSkipVerify bool, disableHTTP2 bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if insecureSkipVerify {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+	if disableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+func setHeaders(req *http.Request, opts *Options) {
+	if opts.SafeSearch {
+		req.Header.Set("Cookie", ConsentCookie+"; "+SafetyModeCookie)
+	} else {
+		req.Header.Set("Cookie", ConsentCookie)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+}
+
+func dryRun(req *http.Request, body []byte) error {
+	return &dryRunError{request: &DryRunRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	}}
+}
+
+func postContext(context Context, opts *Options) Context {
+	if opts.SafeSearch {
+		context.User.EnableSafetyMode = true
+	}
+	return context
+}
+
+func doGet(rawURL string, opts *Options) ([]byte, error) {
+	reqURL, err := apiURL(rawURL, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := perRequestContext(opts)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	setHeaders(req, opts)
+	if opts.DryRun {
+		return nil, dryRun(req, nil)
+	}
+
+	resp, err := opts.RequestOptions.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	return readBody(resp.Body, opts)
+}
+
+func doPost(rawURL string, opts *Options, payload interface{}, paths jsonPath) (map[string]interface{}, error) {
+	reqURL, err := apiURL(rawURL, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	ctx, cancel := perRequestContext(opts)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, strings.NewReader(string(jsonData)))
+	if err != nil {
+		return nil, err
+	}
+
+	setHeaders(req, opts)
+	req.Header.Set("Content-Type", "application/json")
+	if opts.DryRun {
+		return nil, dryRun(req, jsonData)
+	}
+
+	resp, err := opts.RequestOptions.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("%w: status %d", ErrUnauthorized, resp.StatusCode)
+	}
+
+	body, err := readBody(resp.Body, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeInitialData(opts, body, paths)
+}
+
+func unmarshalJSON(opts *Options, data []byte, v interface{}) error {
+	if opts != nil && opts.UnmarshalJSON != nil {
+		return opts.UnmarshalJSON(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+func requestContext(opts *Options) context.Context {
+	if opts.RequestContext != nil {
+		return opts.RequestContext
+	}
+	return context.Background()
+}
+
+func perRequestContext(opts *Options) (context.Context, context.CancelFunc) {
+	if opts.PerRequestTimeout > 0 {
+		return context.WithTimeout(requestContext(opts), opts.PerRequestTimeout)
+	}
+	return context.WithCancel(requestContext(opts))
+}
+
+func waitRetry(opts *Options, attempt int) error {
+	delay := opts.RetryBackoff
+	if delay <= 0 {
+		return nil
+	}
+	if opts.ExponentialBackoff {
+		for i := 1; i < attempt && delay < time.Minute; i++ {
+			delay *= 2
+		}
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-requestContext(opts).Done():
+		return requestContext(opts).Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func apiURL(rawURL string, opts *Options) (string, error) {
+	if opts.APIHost == "" {
+		return rawURL, nil
+	}
+
+	base, err := parseAPIHost(opts.APIHost)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Host != DefaultAPIHost {
+		return rawURL, nil
+	}
+
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	return u.String(), nil
+}
+
+func parseAPIHost(host string) (*url.URL, error) {
+	raw := host
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil || strings.Trim(u.Path, "/") != "" || u.RawQuery != "" ||
+		(u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAPIHost, host)
+	}
+	return u, nil
 }
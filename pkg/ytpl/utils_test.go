@@ -0,0 +1,79 @@
+package ytpl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTransportOptions(t *testing.T) {
+	tests := []struct {
+		insecureSkipVerify bool
+		disableHTTP2       bool
+	}{
+		{true, false},
+		{false, true},
+		{true, true},
+	}
+
+	for _, tt := range tests {
+		opts := checkArgs("", &Options{InsecureSkipVerify: tt.insecureSkipVerify, DisableHTTP2: tt.disableHTTP2})
+		if opts.RequestOptions == defaultClient {
+			t.Fatalf("%+v: got the default client", tt)
+		}
+
+		transport, ok := opts.RequestOptions.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("%+v: transport is %T", tt, opts.RequestOptions.Transport)
+		}
+		insecure := transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify
+		if insecure != tt.insecureSkipVerify {
+			t.Errorf("%+v: InsecureSkipVerify = %v", tt, insecure)
+		}
+		if transport.ForceAttemptHTTP2 == tt.disableHTTP2 {
+			t.Errorf("%+v: ForceAttemptHTTP2 = %v", tt, transport.ForceAttemptHTTP2)
+		}
+		if tt.disableHTTP2 && (transport.TLSNextProto == nil || len(transport.TLSNextProto) != 0) {
+			t.Errorf("%+v: TLSNextProto = %v, want an empty map", tt, transport.TLSNextProto)
+		}
+
+		again := checkArgs("", &Options{InsecureSkipVerify: tt.insecureSkipVerify, DisableHTTP2: tt.disableHTTP2})
+		if again.RequestOptions != opts.RequestOptions {
+			t.Errorf("%+v: a new client was built for the same settings", tt)
+		}
+	}
+
+	if opts := checkArgs("", nil); opts.RequestOptions != defaultClient {
+		t.Error("default options did not use the default client")
+	}
+	custom := &http.Client{}
+	if opts := checkArgs("", &Options{RequestOptions: custom, InsecureSkipVerify: true}); opts.RequestOptions != custom {
+		t.Error("a custom client was replaced")
+	}
+}
+
+func TestPerRequestTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(done) })
+	t.Cleanup(clearCache)
+	clearCache()
+
+	start := time.Now()
+	_, err := GetPlaylist(fixturePlaylistID, &Options{APIHost: server.URL, Retries: NoRetries, PerRequestTimeout: 100 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want a deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("GetPlaylist took %v with a 100ms timeout", elapsed)
+	}
+}
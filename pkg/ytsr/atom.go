@@ -0,0 +1,78 @@
+package ytsr
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+}
+
+func (r *SearchResult) WriteAtom(w io.Writer) error {
+	updated := time.Now().UTC().Format(time.RFC3339)
+
+	params := url.Values{}
+	params.Set("search_query", r.Query)
+	searchURL := BaseSearchURL + "?" + params.Encode()
+
+	feed := atomFeed{
+		ID:      searchURL,
+		Title:   r.Query,
+		Updated: updated,
+		Link:    atomLink{Href: searchURL, Rel: "alternate"},
+		Author:  atomAuthor{Name: "YouTube"},
+	}
+
+	for _, item := range r.Items {
+		entry := atomEntry{
+			ID:      "yt:" + item.Type + ":" + item.ID,
+			Title:   item.Name,
+			Updated: updated,
+			Link:    atomLink{Href: item.URL, Rel: "alternate"},
+		}
+		if item.Author != nil && item.Author.Name != "" {
+			entry.Author = &atomAuthor{Name: item.Author.Name, URI: item.Author.URL}
+		} else if item.Owner != nil && item.Owner.Name != "" {
+			entry.Author = &atomAuthor{Name: item.Owner.Name, URI: item.Owner.URL}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,86 @@
+package ytsr
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"regexp"
+	"testing"
+)
+
+var atomUpdatedRegex = regexp.MustCompile(`<updated>[^<]*</updated>`)
+
+func atomFixture() *SearchResult {
+	return &SearchResult{
+		Query: "rock & roll <live>",
+		Items: []SearchItem{
+			{
+				Type:   "video",
+				ID:     "dQw4w9WgXcQ",
+				Name:   "Salt & Pepper <Official Video>",
+				URL:    "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+				Author: &Author{Name: "Salt & Pepper", URL: "https://www.youtube.com/@saltnpepper"},
+			},
+			{
+				Type:  "playlist",
+				ID:    "PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP",
+				Name:  "Rock & Roll Classics",
+				URL:   "https://www.youtube.com/playlist?list=PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP",
+				Owner: &Owner{Name: "Tom & Jerry", URL: "https://www.youtube.com/@tomandjerry"},
+			},
+		},
+	}
+}
+
+func TestWriteAtomGolden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := atomFixture().WriteAtom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := atomUpdatedRegex.ReplaceAll(buf.Bytes(), []byte("<updated></updated>"))
+
+	want, err := os.ReadFile("testdata/search.atom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("WriteAtom() output differs from testdata/search.atom:\n%s", got)
+	}
+}
+
+func TestWriteAtomRoundTrip(t *testing.T) {
+	result := atomFixture()
+
+	var buf bytes.Buffer
+	if err := result.WriteAtom(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+
+	if feed.Title != result.Query {
+		t.Errorf("feed title = %q, want %q", feed.Title, result.Query)
+	}
+	if feed.Link.Href != "https://www.youtube.com/results?search_query=rock+%26+roll+%3Clive%3E" {
+		t.Errorf("feed link = %q", feed.Link.Href)
+	}
+	if len(feed.Entries) != len(result.Items) {
+		t.Fatalf("got %d entries, want %d", len(feed.Entries), len(result.Items))
+	}
+
+	for i, entry := range feed.Entries {
+		item := result.Items[i]
+		if entry.ID != "yt:"+item.Type+":"+item.ID || entry.Title != item.Name || entry.Link.Href != item.URL {
+			t.Errorf("entry %d = %+v", i, entry)
+		}
+	}
+	if author := feed.Entries[0].Author; author == nil || author.Name != "Salt & Pepper" {
+		t.Errorf("first entry author = %+v", author)
+	}
+	if author := feed.Entries[1].Author; author == nil || author.Name != "Tom & Jerry" {
+		t.Errorf("second entry author = %+v", author)
+	}
+}
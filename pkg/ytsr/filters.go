@@ -0,0 +1,204 @@
+package ytsr
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var uploadDateFilters = map[string]uint64{
+	"hour":  1,
+	"today": 2,
+	"week":  3,
+	"month": 4,
+	"year":  5,
+}
+
+var typeFilters = map[string]uint64{
+	"video":    1,
+	"channel":  2,
+	"playlist": 3,
+	"movie":    4,
+}
+
+var durationFilters = map[string]uint64{
+	"short":  1,
+	"long":   2,
+	"medium": 3,
+}
+
+var featureFilters = map[string]uint64{
+	"hd":              4,
+	"subtitles":       5,
+	"creativecommons": 6,
+	"3d":              7,
+	"live":            8,
+	"purchased":       9,
+	"4k":              14,
+	"360":             15,
+	"location":        23,
+	"hdr":             25,
+	"vr180":           26,
+}
+
+var sortByFilters = map[string]uint64{
+	"relevance":  0,
+	"rating":     1,
+	"uploadDate": 2,
+	"viewCount":  3,
+}
+
+func (f *Filters) Encode() (string, error) {
+	if f == nil {
+		return "", nil
+	}
+
+	var filters []byte
+
+	if f.UploadDate != "" {
+		value, ok := uploadDateFilters[f.UploadDate]
+		if !ok {
+			return "", fmt.Errorf("unknown upload date filter: %s", f.UploadDate)
+		}
+		filters = appendVarintField(filters, 1, value)
+	}
+
+	if f.Type != "" {
+		value, ok := typeFilters[f.Type]
+		if !ok {
+			return "", fmt.Errorf("unknown type filter: %s", f.Type)
+		}
+		filters = appendVarintField(filters, 2, value)
+	}
+
+	if f.Duration != "" {
+		value, ok := durationFilters[f.Duration]
+		if !ok {
+			return "", fmt.Errorf("unknown duration filter: %s", f.Duration)
+		}
+		filters = appendVarintField(filters, 3, value)
+	}
+
+	for _, feature := range f.Features {
+		field, ok := featureFilters[strings.ToLower(feature)]
+		if !ok {
+			return "", fmt.Errorf("unknown feature filter: %s", feature)
+		}
+		filters = appendVarintField(filters, field, 1)
+	}
+
+	var params []byte
+
+	if f.SortBy != "" {
+		value, ok := sortByFilters[f.SortBy]
+		if !ok {
+			return "", fmt.Errorf("unknown sort filter: %s", f.SortBy)
+		}
+		if value != 0 {
+			params = appendVarintField(params, 1, value)
+		}
+	}
+
+	if len(filters) > 0 {
+		params = appendVarint(params, 2<<3|2)
+		params = appendVarint(params, uint64(len(filters)))
+		params = append(params, filters...)
+	}
+
+	if len(params) == 0 {
+		return "", nil
+	}
+
+	return url.QueryEscape(base64.StdEncoding.EncodeToString(params)), nil
+}
+
+func appendVarintField(buf []byte, field uint64, value uint64) []byte {
+	buf = appendVarint(buf, field<<3)
+	return appendVarint(buf, value)
+}
+
+func appendVarint(buf []byte, value uint64) []byte {
+	for value >= 0x80 {
+		buf = append(buf, byte(value)|0x80)
+		value >>= 7
+	}
+	return append(buf, byte(value))
+}
+
+func GetFilters(query string, options *Options) (map[string][]Filter, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query is mandatory")
+	}
+
+	opts := checkArgs(query, options)
+
+	parsed, err := getInitialData(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]Filter)
+	collectFilterGroups(parsed.JSON, opts.Query, groups)
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no search filters found")
+	}
+	return groups, nil
+}
+
+func collectFilterGroups(obj interface{}, query string, groups map[string][]Filter) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if group, ok := v["searchFilterGroupRenderer"].(map[string]interface{}); ok {
+			title := parseText(group["title"])
+			rawFilters, _ := group["filters"].([]interface{})
+			for _, rawFilter := range rawFilters {
+				filterMap, _ := rawFilter.(map[string]interface{})
+				if renderer, ok := filterMap["searchFilterRenderer"].(map[string]interface{}); ok {
+					groups[title] = append(groups[title], parseFilter(renderer, query))
+				}
+			}
+			return
+		}
+		for _, value := range v {
+			collectFilterGroups(value, query, groups)
+		}
+	case []interface{}:
+		for _, value := range v {
+			collectFilterGroups(value, query, groups)
+		}
+	}
+}
+
+func parseFilter(renderer map[string]interface{}, query string) Filter {
+	filter := Filter{
+		Label:  parseText(renderer["label"]),
+		Status: "enabled",
+	}
+
+	switch status, _ := renderer["status"].(string); status {
+	case "FILTER_STATUS_SELECTED":
+		filter.Status = "selected"
+	case "FILTER_STATUS_DISABLED":
+		filter.Status = "disabled"
+	}
+
+	if endpoint, ok := renderer["navigationEndpoint"].(map[string]interface{}); ok {
+		if searchEndpoint, ok := endpoint["searchEndpoint"].(map[string]interface{}); ok {
+			filter.Param, _ = searchEndpoint["params"].(string)
+		}
+	}
+
+	if filter.Param != "" {
+		sp, err := url.QueryUnescape(filter.Param)
+		if err != nil {
+			sp = filter.Param
+		}
+		params := url.Values{}
+		params.Set("search_query", query)
+		params.Set("sp", sp)
+		filter.URL = BaseSearchURL + "?" + params.Encode()
+	}
+
+	return filter
+}
@@ -0,0 +1,51 @@
+package ytsr
+
+import "testing"
+
+func TestFiltersEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters *Filters
+		want    string
+	}{
+		{"nil", nil, ""},
+		{"empty", &Filters{}, ""},
+		{"relevance", &Filters{SortBy: "relevance"}, ""},
+		{"today", &Filters{UploadDate: "today"}, "EgIIAg%3D%3D"},
+		{"video", &Filters{Type: "video"}, "EgIQAQ%3D%3D"},
+		{"long", &Filters{Duration: "long"}, "EgIYAg%3D%3D"},
+		{"view count", &Filters{SortBy: "viewCount"}, "CAM%3D"},
+		{"upload date", &Filters{SortBy: "uploadDate"}, "CAI%3D"},
+		{"hd", &Filters{Features: []string{"HD"}}, "EgIgAQ%3D%3D"},
+		{"live", &Filters{Features: []string{"live"}}, "EgJAAQ%3D%3D"},
+		{"4k", &Filters{Features: []string{"4k"}}, "EgJwAQ%3D%3D"},
+		{"combined", &Filters{UploadDate: "today", Duration: "long", SortBy: "viewCount"}, "CAMSBAgCGAI%3D"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.filters.Encode()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Encode() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFiltersEncodeUnknown(t *testing.T) {
+	tests := []*Filters{
+		{UploadDate: "decade"},
+		{Type: "podcast"},
+		{Duration: "epic"},
+		{Features: []string{"8k"}},
+		{SortBy: "random"},
+	}
+
+	for _, filters := range tests {
+		if _, err := filters.Encode(); err == nil {
+			t.Errorf("Encode(%+v) returned no error", *filters)
+		}
+	}
+}
@@ -2,25 +2,50 @@ package ytsr
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/Victiniiiii/ytpl-ytsr-go/pkg/ytpl"
+)
+
+const (
+	BaseSearchURL  = "https://www.youtube.com/results"
+	BaseAPIURL     = "https://www.youtube.com/youtubei/v1/search"
+	BaseVideoURL   = "https://www.youtube.com/watch?v="
+	BaseShortsURL  = "https://www.youtube.com/shorts/"
+	BaseURL        = "https://www.youtube.com/"
+	ConsentCookie  = "SOCS=CAI"
+	DefaultAPIHost = "www.youtube.com"
+	NoRetries      = -1
 )
 
+var (
+	ErrUnknownRenderer = errors.New("unknown renderer")
+	ErrBudgetExceeded  = errors.New("download budget exceeded")
+	ErrInvalidAPIHost  = errors.New("invalid api host")
+)
+
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
 const (
-	BaseSearchURL = "https://www.youtube.com/results"
-	BaseAPIURL    = "https://www.youtube.com/youtubei/v1/search"
-	BaseVideoURL  = "https://www.youtube.com/watch?v="
-	BaseURL       = "https://www.youtube.com/"
-	ConsentCookie = "SOCS=CAI"
+	defaultClientVersion = "2.20240606.06.00"
+	defaultRetries       = 3
+	minTokenLength       = 12
 )
 
 var cache = &Cache{
-	ClientVersion:  "2.20240606.06.00",
+	ClientVersion:  defaultClientVersion,
 	PlaylistParams: "EgIQAw%3D%3D",
 }
 
@@ -36,65 +61,218 @@ func DefaultOptions() *Options {
 }
 
 func Search(searchString string, options *Options) (*SearchResult, error) {
-	return search(searchString, options, 3)
+	result, err := search(searchString, options, retryCount(options))
+
+	var dryErr *dryRunError
+	if errors.As(err, &dryErr) {
+		return &SearchResult{Query: searchString, DryRun: dryErr.request}, nil
+	}
+	return result, err
 }
 
-func search(searchString string, options *Options, retries int) (*SearchResult, error) {
-	if retries == 2 {
-		cache.mu.Lock()
-		cache.ClientVersion = ""
-		cache.PlaylistParams = ""
-		cache.mu.Unlock()
+func SearchTopPlaylist(query string, searchOpts *Options, plOpts *ytpl.Options) (*ytpl.PlaylistInfo, error) {
+	if searchOpts == nil {
+		searchOpts = DefaultOptions()
 	}
 
-	if retries == 0 {
-		return nil, fmt.Errorf("unable to find JSON")
+	opts := *searchOpts
+	opts.Type = "playlist"
+
+	results, err := Search(query, &opts)
+	if err != nil {
+		return nil, err
 	}
 
-	opts := checkArgs(searchString, options)
+	for _, item := range results.Items {
+		if item.Type == "playlist" && item.ID != "" {
+			return ytpl.GetPlaylist(item.ID, plOpts)
+		}
+	}
 
-	var parsed *ParsedData
-	var err error
+	return nil, fmt.Errorf("no playlist found for %q", query)
+}
 
-	cache.mu.RLock()
-	needsInitialRequest := !opts.SafeSearch || cache.ClientVersion == "" || cache.PlaylistParams == ""
-	cache.mu.RUnlock()
+func SearchContinuation(token string, options *Options) (*SearchResult, error) {
+	if token == "" {
+		return nil, errors.New("continuation token is mandatory")
+	}
 
-	if needsInitialRequest {
-		parsed, err = getInitialData(opts)
-		if err != nil {
-			return nil, err
+	searchString := "continuation"
+	if options != nil && options.Query != "" {
+		searchString = options.Query
+	}
+
+	c := cache
+	if options != nil && options.Cache != nil {
+		c = options.Cache
+	}
+	clientVersion, _ := c.get()
+	if clientVersion == "" {
+		clientVersion = defaultClientVersion
+	}
+
+	return SearchFromCursor(SearchCursor{
+		Token:         token,
+		ClientVersion: clientVersion,
+		Query:         searchString,
+	}, options)
+}
+
+func SearchFromCursor(cursor SearchCursor, options *Options) (*SearchResult, error) {
+	if err := cursor.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := checkArgs(cursor.Query, options)
+	if cursor.GL != "" {
+		opts.GL = cursor.GL
+	}
+	if cursor.HL != "" {
+		opts.HL = cursor.HL
+	}
+
+	jsonResp, err := doPost(BaseAPIURL, opts, map[string]interface{}{
+		"context":      buildPostContext(cursor.ClientVersion, opts),
+		"continuation": cursor.Token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseContinuationResponse(jsonResp, cursor.ClientVersion, opts)
+}
+
+func (c SearchCursor) Validate() error {
+	if c.Token == "" {
+		return errors.New("cursor is missing a continuation token")
+	}
+	if c.ClientVersion == "" {
+		return errors.New("cursor is missing a client version")
+	}
+	if c.Query == "" {
+		return errors.New("cursor is missing a query")
+	}
+	return nil
+}
+
+func (c SearchCursor) MarshalJSON() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	type cursor SearchCursor
+	return json.Marshal(cursor(c))
+}
+
+func (c *SearchCursor) UnmarshalJSON(data []byte) error {
+	type cursor SearchCursor
+	var decoded cursor
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if err := SearchCursor(decoded).Validate(); err != nil {
+		return err
+	}
+	*c = SearchCursor(decoded)
+	return nil
+}
+
+func search(searchString string, options *Options, retries int) (*SearchResult, error) {
+	opts := checkArgs(searchString, options)
+
+	clientVersion, _ := opts.Cache.get()
+
+	if clientVersion != "" {
+		result, err := searchDirect(clientVersion, opts)
+		if err == nil && !(opts.RetryOnEmpty && result.Stats.Parsed == 0 && result.Stats.SkippedUnknown == 0) {
+			return result, nil
 		}
-		saveCache(parsed, opts)
-	} else {
-		parsed = &ParsedData{
-			Context: buildPostContext(cache.ClientVersion, opts),
+		var dryErr *dryRunError
+		if errors.Is(err, ErrBudgetExceeded) || errors.As(err, &dryErr) {
+			return nil, err
 		}
 	}
 
+	parsed, err := getInitialData(opts)
+	if err != nil {
+		return nil, err
+	}
+	saveCache(parsed, opts)
+
+	payload, err := searchPayload(parsed.Context, opts.Query, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	if opts.Type == "playlist" {
-		parsed.JSON, err = doPost(BaseAPIURL, opts, map[string]interface{}{
-			"context": parsed.Context,
-			"query":   searchString,
-		})
+		parsed.JSON, err = doPost(searchAPIURL(opts), opts, payload)
 		if err != nil {
-			return nil, fmt.Errorf("cannot search for playlist: %v", err)
+			return nil, fmt.Errorf("cannot search for playlist: %w", err)
 		}
 	} else if opts.SafeSearch || parsed.JSON == nil {
-		parsed.JSON, err = doPost(BaseAPIURL, opts, map[string]interface{}{
-			"context": parsed.Context,
-			"query":   searchString,
-		})
-		if err != nil && retries == 1 {
+		parsed.JSON, err = doPost(searchAPIURL(opts), opts, payload)
+		var dryErr *dryRunError
+		if err != nil && (retries <= 0 || errors.Is(err, ErrBudgetExceeded) || errors.As(err, &dryErr)) {
 			return nil, err
 		}
 	}
 
 	if parsed.JSON == nil {
-		return search(searchString, options, retries-1)
+		if retries <= 0 {
+			return nil, fmt.Errorf("unable to find JSON")
+		}
+
+		return retrySearch(searchString, opts, retries)
 	}
 
-	return parseResponse(parsed, opts)
+	result, err := parseResponse(parsed, opts)
+	if err == nil && opts.RetryOnEmpty && retries > 0 && result.Stats.Parsed == 0 && result.Stats.SkippedUnknown == 0 {
+		return retrySearch(searchString, opts, retries)
+	}
+	return result, err
+}
+
+func searchDirect(clientVersion string, opts *Options) (*SearchResult, error) {
+	context := buildPostContext(clientVersion, opts)
+	payload, err := searchPayload(context, opts.Query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonResp, err := doPost(searchAPIURL(opts), opts, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseResponse(&ParsedData{JSON: jsonResp, Context: context}, opts)
+}
+
+func searchAPIURL(opts *Options) string {
+	if len(opts.ExtraParams) == 0 {
+		return BaseAPIURL
+	}
+
+	params := url.Values{}
+	for k, v := range opts.ExtraParams {
+		params.Set(k, v)
+	}
+	return BaseAPIURL + "?" + params.Encode()
+}
+
+func retrySearch(searchString string, opts *Options, retries int) (*SearchResult, error) {
+	if err := waitRetry(opts, retryCount(opts)-retries+1); err != nil {
+		return nil, err
+	}
+	return search(searchString, opts, retries-1)
+}
+
+func retryCount(options *Options) int {
+	if options == nil || options.Retries == 0 {
+		return defaultRetries
+	}
+	if options.Retries < 0 {
+		return 0
+	}
+	return options.Retries
 }
 
 func checkArgs(searchString string, options *Options) *Options {
@@ -110,7 +288,7 @@ func checkArgs(searchString string, options *Options) *Options {
 
 	opts.Query = searchString
 
-	if opts.Type != "video" && opts.Type != "playlist" {
+	if opts.Type != "video" && opts.Type != "playlist" && opts.Type != "short" && opts.Type != "channel" && opts.Type != "movie" {
 		opts.Type = "video"
 	}
 
@@ -126,12 +304,30 @@ func checkArgs(searchString string, options *Options) *Options {
 		opts.HL = "en"
 	}
 
+	if opts.Client == nil {
+		opts.Client = defaultClient
+		if opts.InsecureSkipVerify || opts.DisableHTTP2 {
+			opts.Client = transportClient(opts.InsecureSkipVerify, opts.DisableHTTP2)
+		}
+	}
+	if opts.Timeout > 0 && opts.Client.Timeout != opts.Timeout {
+		client := *opts.Client
+		client.Timeout = opts.Timeout
+		opts.Client = &client
+	}
+
+	if opts.Cache == nil {
+		opts.Cache = cache
+	}
+
 	if strings.HasPrefix(searchString, BaseURL) {
 		u, err := url.Parse(searchString)
 		if err == nil && u.Path == "/results" && u.Query().Get("sp") != "" {
 			if u.Query().Get("search_query") == "" {
 				panic("filter links have to include a 'search_query' query")
 			}
+			opts.Query = u.Query().Get("search_query")
+			opts.sp = u.Query().Get("sp")
 		}
 	}
 
@@ -139,28 +335,45 @@ func checkArgs(searchString string, options *Options) *Options {
 }
 
 func getInitialData(opts *Options) (*ParsedData, error) {
-	client := &http.Client{}
-
 	params := url.Values{}
+	for k, v := range opts.ExtraParams {
+		params.Set(k, v)
+	}
 	params.Set("search_query", opts.Query)
 	params.Set("gl", opts.GL)
 	params.Set("hl", opts.HL)
 
-	req, err := http.NewRequest("GET", BaseSearchURL+"?"+params.Encode(), nil)
+	sp, err := searchParams(opts)
+	if err != nil {
+		return nil, err
+	}
+	if sp != "" {
+		params.Set("sp", sp)
+	}
+
+	reqURL, err := apiURL(BaseSearchURL+"?"+params.Encode(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(requestContext(opts), "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Cookie", ConsentCookie)
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	if opts.DryRun {
+		return nil, dryRun(req, nil)
+	}
 
-	resp, err := client.Do(req)
+	resp, err := opts.Client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(resp.Body, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -168,8 +381,55 @@ func getInitialData(opts *Options) (*ParsedData, error) {
 	return parseBody(string(body), opts)
 }
 
+func searchParams(opts *Options) (string, error) {
+	if opts.sp != "" {
+		return opts.sp, nil
+	}
+
+	filters := opts.Filters
+	if opts.LiveOnly {
+		liveFilters := Filters{}
+		if filters != nil {
+			liveFilters = *filters
+		}
+		liveFilters.Features = append(append([]string(nil), liveFilters.Features...), "live")
+		filters = &liveFilters
+	}
+	if opts.Type == "movie" && (filters == nil || filters.Type == "") {
+		movieFilters := Filters{}
+		if filters != nil {
+			movieFilters = *filters
+		}
+		movieFilters.Type = "movie"
+		filters = &movieFilters
+	}
+
+	sp, err := filters.Encode()
+	if err != nil || sp == "" {
+		return "", err
+	}
+	return url.QueryUnescape(sp)
+}
+
+func searchPayload(context *Context, searchString string, opts *Options) (map[string]interface{}, error) {
+	payload := map[string]interface{}{
+		"context": context,
+		"query":   searchString,
+	}
+
+	sp, err := searchParams(opts)
+	if err != nil {
+		return nil, err
+	}
+	if sp != "" {
+		payload["params"] = sp
+	}
+
+	return payload, nil
+}
+
 func extractClientVersion(jsonData map[string]interface{}, body string) (string, error) {
-	fallbackVersion := "2.20240606.06.00"
+	fallbackVersion := defaultClientVersion
 
 	if respCtx, ok := jsonData["responseContext"].(map[string]interface{}); ok {
 		if stp, ok := respCtx["serviceTrackingParams"].([]interface{}); ok {
@@ -228,19 +488,50 @@ func buildPostContext(clientVersion string, opts *Options) *Context {
 	return context
 }
 
+func ResetCache() {
+	cache.Reset()
+}
+
+func SetClientVersion(version string) {
+	cache.SetClientVersion(version)
+}
+
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ClientVersion = ""
+	c.PlaylistParams = ""
+}
+
+func (c *Cache) SetClientVersion(version string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ClientVersion = version
+}
+
+func (c *Cache) get() (string, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.ClientVersion, c.PlaylistParams
+}
+
 func saveCache(parsed *ParsedData, opts *Options) {
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
+	c := opts.Cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	if parsed.Context != nil && parsed.Context.Client != nil {
 		if cv, ok := parsed.Context.Client["clientVersion"].(string); ok {
-			cache.ClientVersion = cv
+			c.ClientVersion = cv
 		}
 	}
 
 	playlistParams := getPlaylistParams(parsed)
 	if playlistParams != "" {
-		cache.PlaylistParams = playlistParams
+		c.PlaylistParams = playlistParams
 	}
 }
 
@@ -255,15 +546,23 @@ func getPlaylistParams(parsed *ParsedData) string {
 	return ""
 }
 
-func doPost(url string, opts *Options, payload map[string]interface{}) (map[string]interface{}, error) {
-	client := &http.Client{}
+func doPost(rawURL string, opts *Options, payload map[string]interface{}) (map[string]interface{}, error) {
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+
+	reqURL, err := apiURL(rawURL+sep+"prettyPrint=false", opts)
+	if err != nil {
+		return nil, err
+	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url+"?prettyPrint=false", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(requestContext(opts), "POST", reqURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, err
 	}
@@ -271,18 +570,171 @@ func doPost(url string, opts *Options, payload map[string]interface{}) (map[stri
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Cookie", ConsentCookie)
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	if opts.DryRun {
+		return nil, dryRun(req, jsonData)
+	}
 
-	resp, err := client.Do(req)
+	resp, err := opts.Client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	body, err := readBody(resp.Body, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	var result map[string]interface{}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	err = unmarshalJSON(opts, body, &result)
 	return result, err
 }
 
+type transportKey struct {
+	insecureSkipVerify bool
+	disableHTTP2       bool
+}
+
+var (
+	transportClientsMu sync.Mutex
+	transportClients   = map[transportKey]*http.Client{}
+)
+
+func transportClient(insecureSkipVerify bool, disableHTTP2 bool) *http.Client {
+	transportClientsMu.Lock()
+	defer transportClientsMu.Unlock()
+
+	key := transportKey{insecureSkipVerify, disableHTTP2}
+	if client, ok := transportClients[key]; ok {
+		return client
+	}
+
+	client := &http.Client{
+		Timeout:   defaultClient.Timeout,
+		Transport: newTransport(insecureSkipVerify, disableHTTP2),
+	}
+	transportClients[key] = client
+	return client
+}
+
+func newTransport(insecureSkipVerify bool, disableHTTP2 bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if insecureSkipVerify {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+	if disableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+func readBody(r io.Reader, opts *Options) ([]byte, error) {
+	if opts.MaxTotalBytes <= 0 {
+		return io.ReadAll(r)
+	}
+
+	remaining := opts.MaxTotalBytes - opts.bytesRead
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, remaining+1))
+	opts.bytesRead += int64(len(body))
+	if err != nil {
+		return nil, err
+	}
+	if opts.bytesRead > opts.MaxTotalBytes {
+		return nil, ErrBudgetExceeded
+	}
+	return body, nil
+}
+
+func dryRun(req *http.Request, body []byte) error {
+	return &dryRunError{request: &DryRunRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	}}
+}
+
+func requestContext(opts *Options) context.Context {
+	if opts.RequestContext != nil {
+		return opts.RequestContext
+	}
+	return context.Background()
+}
+
+func waitRetry(opts *Options, attempt int) error {
+	delay := opts.RetryBackoff
+	if delay <= 0 {
+		return nil
+	}
+	if opts.ExponentialBackoff {
+		for i := 1; i < attempt && delay < time.Minute; i++ {
+			delay *= 2
+		}
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-requestContext(opts).Done():
+		return requestContext(opts).Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func apiURL(rawURL string, opts *Options) (string, error) {
+	if opts.APIHost == "" {
+		return rawURL, nil
+	}
+
+	base, err := parseAPIHost(opts.APIHost)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Host != DefaultAPIHost {
+		return rawURL, nil
+	}
+
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	return u.String(), nil
+}
+
+func parseAPIHost(host string) (*url.URL, error) {
+	raw := host
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil || strings.Trim(u.Path, "/") != "" || u.RawQuery != "" ||
+		(u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAPIHost, host)
+	}
+	return u, nil
+}
+
+func unmarshalJSON(opts *Options, data []byte, v interface{}) error {
+	if opts.UnmarshalJSON != nil {
+		return opts.UnmarshalJSON(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
+
 func findTwoColumnSearchResultsRenderer(m map[string]interface{}) (map[string]interface{}, bool) {
 	for k, v := range m {
 		if k == "twoColumnSearchResultsRenderer" {
@@ -339,13 +791,12 @@ func prepareThumbnails(thumbnails []interface{}) []Thumbnail {
 		}
 	}
 
-	for i := 0; i < len(result)-1; i++ {
-		for j := i + 1; j < len(result); j++ {
-			if result[i].Width < result[j].Width {
-				result[i], result[j] = result[j], result[i]
-			}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Width != result[j].Width {
+			return result[i].Width > result[j].Width
 		}
-	}
+		return result[i].Height > result[j].Height
+	})
 
 	return result
 }
@@ -0,0 +1,500 @@
+package ytsr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fixtureRequest struct {
+	Method  string
+	Path    string
+	Query   url.Values
+	Payload map[string]interface{}
+}
+
+type fixtureServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []fixtureRequest
+}
+
+func readFixture(tb testing.TB, name string) []byte {
+	tb.Helper()
+
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return data
+}
+
+func newFixtureServer(t *testing.T) *fixtureServer {
+	t.Helper()
+
+	page := readFixture(t, "search.html")
+	results := readFixture(t, "search.json")
+	continuation := readFixture(t, "continuation.json")
+
+	fs := &fixtureServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := fixtureRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()}
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&req.Payload)
+		}
+		fs.mu.Lock()
+		fs.requests = append(fs.requests, req)
+		fs.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/results":
+			w.Write(page)
+		case r.Method == http.MethodPost && r.URL.Path == "/youtubei/v1/search" && req.Payload["continuation"] != nil:
+			w.Write(continuation)
+		case r.Method == http.MethodPost && r.URL.Path == "/youtubei/v1/search":
+			w.Write(results)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(fs.Close)
+
+	return fs
+}
+
+func (fs *fixtureServer) Requests() []fixtureRequest {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return append([]fixtureRequest(nil), fs.requests...)
+}
+
+func fixtureOptions(fs *fixtureServer) *Options {
+	opts := DefaultOptions()
+	opts.APIHost = fs.URL
+	opts.Cache = &Cache{}
+	return opts
+}
+
+func TestSearchContinuation(t *testing.T) {
+	fs := newFixtureServer(t)
+
+	opts := fixtureOptions(fs)
+	opts.Limit = 20
+	first, err := Search("lofi", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Items) != 20 || first.Continuation == "" {
+		t.Fatalf("first page: %d items, continuation %q", len(first.Items), first.Continuation)
+	}
+
+	second, err := SearchContinuation(first.Continuation, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Items) != 20 || second.Items[0].Name != "Result 21" || second.Items[19].Name != "Result 40" {
+		t.Errorf("second page: %d items starting at %+v", len(second.Items), second.Items)
+	}
+	if second.Continuation == "" || second.Cursor == nil {
+		t.Error("second page has no continuation")
+	}
+}
+
+func TestSearchContinuationLimit(t *testing.T) {
+	fs := newFixtureServer(t)
+
+	opts := fixtureOptions(fs)
+	opts.Limit = 5
+	first, err := Search("lofi", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Items) != 5 {
+		t.Errorf("got %d items, want 5", len(first.Items))
+	}
+	if first.Continuation != "" || first.Cursor != nil {
+		t.Errorf("continuation %q exposed for a partly consumed page", first.Continuation)
+	}
+
+	token := "EqMDEgVsb2ZpGpYDU0JTQ0FRdHpjbU5vTURBd01EQXdNWUlCQzNOeVkyZ3dNREF3TURBeWdnRUxjM0pqYURBd01EQXdNRE09"
+	next, err := SearchContinuation(token, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Items) != 5 || next.Continuation != "" {
+		t.Errorf("continuation page: %d items, continuation %q", len(next.Items), next.Continuation)
+	}
+}
+
+func TestSearchDirectWithClientVersionOnly(t *testing.T) {
+	fs := newFixtureServer(t)
+
+	opts := fixtureOptions(fs)
+	opts.Cache = &Cache{ClientVersion: "2.20240701.00.00"}
+	result, err := Search("lofi", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 10 {
+		t.Errorf("got %d items, want 10", len(result.Items))
+	}
+
+	requests := fs.Requests()
+	if len(requests) != 1 || requests[0].Method != http.MethodPost {
+		t.Fatalf("requests = %+v, want a single search POST", requests)
+	}
+	if version, _ := opts.Cache.get(); version != "2.20240701.00.00" {
+		t.Errorf("cached client version = %q", version)
+	}
+}
+
+func TestSearchExtraParams(t *testing.T) {
+	for _, cache := range []*Cache{{}, {ClientVersion: "2.20240701.00.00"}} {
+		fs := newFixtureServer(t)
+
+		opts := fixtureOptions(fs)
+		opts.Cache = cache
+		opts.ExtraParams = map[string]string{"persist_gl": "1", "gl": "DE"}
+		if _, err := Search("lofi", opts); err != nil {
+			t.Fatal(err)
+		}
+
+		requests := fs.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("requests = %+v, want one", requests)
+		}
+		if got := requests[0].Query.Get("persist_gl"); got != "1" {
+			t.Errorf("%s %s: persist_gl = %q, want 1", requests[0].Method, requests[0].Path, got)
+		}
+		if requests[0].Method == http.MethodGet && requests[0].Query.Get("gl") != "US" {
+			t.Errorf("gl = %q, ExtraParams must not override it", requests[0].Query.Get("gl"))
+		}
+	}
+}
+
+func TestTransportOptions(t *testing.T) {
+	tests := []struct {
+		insecureSkipVerify bool
+		disableHTTP2       bool
+	}{
+		{true, false},
+		{false, true},
+		{true, true},
+	}
+
+	for _, tt := range tests {
+		opts := checkArgs("lofi", &Options{InsecureSkipVerify: tt.insecureSkipVerify, DisableHTTP2: tt.disableHTTP2})
+		if opts.Client == defaultClient {
+			t.Fatalf("%+v: got the default client", tt)
+		}
+
+		transport, ok := opts.Client.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("%+v: transport is %T", tt, opts.Client.Transport)
+		}
+		insecure := transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify
+		if insecure != tt.insecureSkipVerify {
+			t.Errorf("%+v: InsecureSkipVerify = %v", tt, insecure)
+		}
+		if transport.ForceAttemptHTTP2 == tt.disableHTTP2 {
+			t.Errorf("%+v: ForceAttemptHTTP2 = %v", tt, transport.ForceAttemptHTTP2)
+		}
+		if tt.disableHTTP2 && (transport.TLSNextProto == nil || len(transport.TLSNextProto) != 0) {
+			t.Errorf("%+v: TLSNextProto = %v, want an empty map", tt, transport.TLSNextProto)
+		}
+
+		again := checkArgs("lofi", &Options{InsecureSkipVerify: tt.insecureSkipVerify, DisableHTTP2: tt.disableHTTP2})
+		if again.Client != opts.Client {
+			t.Errorf("%+v: a new client was built for the same settings", tt)
+		}
+	}
+
+	if opts := checkArgs("lofi", nil); opts.Client != defaultClient {
+		t.Error("default options did not use the default client")
+	}
+	custom := &http.Client{}
+	if opts := checkArgs("lofi", &Options{Client: custom, InsecureSkipVerify: true}); opts.Client != custom {
+		t.Error("a custom client was replaced")
+	}
+}
+
+func newHangingServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(done) })
+	return server
+}
+
+func TestSearchTimeout(t *testing.T) {
+	server := newHangingServer(t)
+
+	for _, cache := range []*Cache{{}, {ClientVersion: "2.20240701.00.00"}} {
+		opts := DefaultOptions()
+		opts.APIHost = server.URL
+		opts.Cache = cache
+		opts.Retries = NoRetries
+		opts.Timeout = 100 * time.Millisecond
+
+		start := time.Now()
+		_, err := Search("lofi", opts)
+		if err == nil {
+			t.Fatal("Search returned no error from a server that never responds")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Search took %v with a 100ms timeout", elapsed)
+		}
+	}
+}
+
+func TestSearchMaxTotalBytes(t *testing.T) {
+	fs := newFixtureServer(t)
+	results := readFixture(t, "search.json")
+
+	opts := fixtureOptions(fs)
+	opts.Cache = &Cache{ClientVersion: "2.20240701.00.00"}
+	opts.MaxTotalBytes = int64(len(results) / 2)
+	if _, err := Search("lofi", opts); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("got %v, want ErrBudgetExceeded", err)
+	}
+	if requests := fs.Requests(); len(requests) != 1 {
+		t.Errorf("made %d requests after the budget ran out, want 1", len(requests))
+	}
+
+	opts.MaxTotalBytes = int64(len(results))
+	if _, err := Search("lofi", opts); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchRetryOnEmpty(t *testing.T) {
+	page := readFixture(t, "search.html")
+	empty := []byte(strings.Replace(string(page), `"itemSectionRenderer"`, `"messageRenderer"`, 1))
+	results := readFixture(t, "search.json")
+
+	var gets int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && atomic.AddInt32(&gets, 1) == 1:
+			w.Write(empty)
+		case r.Method == http.MethodGet:
+			w.Write(page)
+		default:
+			w.Write(results)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	opts := DefaultOptions()
+	opts.APIHost = server.URL
+	opts.Limit = 20
+	opts.Cache = &Cache{}
+	result, err := Search("lofi", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 0 {
+		t.Fatalf("without RetryOnEmpty: got %d items, want 0", len(result.Items))
+	}
+
+	atomic.StoreInt32(&gets, 0)
+	opts.Cache = &Cache{}
+	opts.RetryOnEmpty = true
+	result, err = Search("lofi", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 20 {
+		t.Errorf("with RetryOnEmpty: got %d items, want 20", len(result.Items))
+	}
+}
+
+func TestSearchReusesCachedClient(t *testing.T) {
+	fs := newFixtureServer(t)
+
+	opts := fixtureOptions(fs)
+	for i := 0; i < 2; i++ {
+		if _, err := Search("lofi", opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	requests := fs.Requests()
+	if len(requests) != 2 || requests[0].Method != http.MethodGet || requests[1].Method != http.MethodPost {
+		t.Fatalf("requests = %+v, want a page load followed by a single search POST", requests)
+	}
+}
+
+func TestSearchCallbacksAcrossPages(t *testing.T) {
+	fs := newFixtureServer(t)
+
+	var filtered, seen []string
+	opts := fixtureOptions(fs)
+	opts.Limit = 10
+	opts.FilterFunc = func(item SearchItem) bool {
+		filtered = append(filtered, item.Name)
+		n, _ := strconv.Atoi(strings.TrimPrefix(item.Name, "Result "))
+		return n%2 == 1
+	}
+	opts.OnItem = func(item SearchItem) bool {
+		seen = append(seen, item.Name)
+		return item.Name != "Result 25"
+	}
+
+	first, err := Search("lofi", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 20 || len(first.Items) != 10 || first.Items[9].Name != "Result 19" {
+		t.Fatalf("first page: filtered %d, kept %+v", len(filtered), first.Items)
+	}
+	if first.Continuation == "" {
+		t.Fatal("first page was fully consumed but has no continuation")
+	}
+
+	second, err := SearchContinuation(first.Continuation, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Items) != 3 || second.Items[2].Name != "Result 25" {
+		t.Errorf("second page: %+v, want to stop at Result 25", second.Items)
+	}
+	if second.Continuation != "" || second.Cursor != nil {
+		t.Errorf("continuation %q exposed after OnItem stopped", second.Continuation)
+	}
+	if len(seen) != 13 || seen[10] != "Result 21" {
+		t.Errorf("OnItem saw %v", seen)
+	}
+}
+
+func TestSearchCursorRoundTrip(t *testing.T) {
+	fs := newFixtureServer(t)
+
+	opts := fixtureOptions(fs)
+	opts.GL = "DE"
+	opts.HL = "de"
+	opts.Limit = 20
+	first, err := Search("lofi", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Cursor == nil {
+		t.Fatal("first page has no cursor")
+	}
+
+	data, err := json.Marshal(first.Cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cursor SearchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		t.Fatal(err)
+	}
+	if cursor != *first.Cursor {
+		t.Fatalf("round trip = %+v, want %+v", cursor, *first.Cursor)
+	}
+
+	resumed := newFixtureServer(t)
+	next, err := SearchFromCursor(cursor, fixtureOptions(resumed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Items) == 0 || next.Items[0].Name != "Result 21" {
+		t.Errorf("resumed page starts at %+v, want Result 21", next.Items)
+	}
+
+	requests := resumed.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("requests = %+v, want a single continuation POST", requests)
+	}
+	client, _ := requests[0].Payload["context"].(map[string]interface{})["client"].(map[string]interface{})
+	if client["gl"] != "DE" || client["hl"] != "de" || client["clientVersion"] != cursor.ClientVersion {
+		t.Errorf("resumed context = %+v", client)
+	}
+	if requests[0].Payload["continuation"] != cursor.Token {
+		t.Errorf("resumed continuation = %v, want %q", requests[0].Payload["continuation"], cursor.Token)
+	}
+}
+
+func TestSearchCursorValidate(t *testing.T) {
+	for _, data := range []string{
+		`{"clientVersion":"2.20240701.00.00","query":"lofi"}`,
+		`{"token":"T1","query":"lofi"}`,
+		`{"token":"T1","clientVersion":"2.20240701.00.00","query":""}`,
+		`{"token":"T1","clientVersion":"2.20240701.00.00","query":"lofi"`,
+	} {
+		var cursor SearchCursor
+		if err := json.Unmarshal([]byte(data), &cursor); err == nil {
+			t.Errorf("Unmarshal(%s) accepted a tampered cursor", data)
+		}
+	}
+
+	if _, err := json.Marshal(SearchCursor{Token: "T1"}); err == nil {
+		t.Error("Marshal accepted a cursor without a client version or query")
+	}
+	if _, err := SearchFromCursor(SearchCursor{Token: "T1", Query: "lofi"}, DefaultOptions()); err == nil {
+		t.Error("SearchFromCursor accepted a cursor without a client version")
+	}
+}
+
+func TestPrepareThumbnailsOrder(t *testing.T) {
+	var thumbnails []interface{}
+	if err := json.Unmarshal([]byte(`[
+		{"url":"https://i.ytimg.com/a.jpg","width":168,"height":94},
+		{"url":"https://i.ytimg.com/b.jpg","width":336,"height":188},
+		{"url":"https://i.ytimg.com/c.jpg","width":336,"height":200},
+		{"url":"https://i.ytimg.com/d.jpg","width":168,"height":94},
+		{"url":"https://i.ytimg.com/e.jpg","width":168,"height":110}
+	]`), &thumbnails); err != nil {
+		t.Fatal(err)
+	}
+
+	var urls []string
+	for _, thumbnail := range prepareThumbnails(thumbnails) {
+		urls = append(urls, thumbnail.URL)
+	}
+	want := []string{
+		"https://i.ytimg.com/c.jpg",
+		"https://i.ytimg.com/b.jpg",
+		"https://i.ytimg.com/e.jpg",
+		"https://i.ytimg.com/a.jpg",
+		"https://i.ytimg.com/d.jpg",
+	}
+	if strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", urls, want)
+	}
+}
+
+func BenchmarkPrepareThumbnails(b *testing.B) {
+	thumbnails := make([]interface{}, 20)
+	for i := range thumbnails {
+		thumbnails[i] = map[string]interface{}{
+			"url":    "https://i.ytimg.com/vi/dQw4w9WgXcQ/" + strconv.Itoa(i) + ".jpg",
+			"width":  float64(120 + (i*37)%400),
+			"height": float64(90 + (i*23)%300),
+		}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		prepareThumbnails(thumbnails)
+	}
+}
@@ -0,0 +1,95 @@
+package ytsr
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Option func(*Options)
+
+func NewOptions(opts ...Option) *Options {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+func WithType(searchType string) Option {
+	return func(o *Options) {
+		o.Type = searchType
+	}
+}
+
+func WithLimit(limit int) Option {
+	return func(o *Options) {
+		o.Limit = limit
+	}
+}
+
+func WithSafeSearch(safeSearch bool) Option {
+	return func(o *Options) {
+		o.SafeSearch = safeSearch
+	}
+}
+
+func WithLocale(gl string, hl string) Option {
+	return func(o *Options) {
+		o.GL = gl
+		o.HL = hl
+	}
+}
+
+func WithUTCOffset(minutes int) Option {
+	return func(o *Options) {
+		o.UTCOffset = minutes
+	}
+}
+
+func WithFilters(filters Filters) Option {
+	return func(o *Options) {
+		o.Filters = &filters
+	}
+}
+
+func WithClient(client *http.Client) Option {
+	return func(o *Options) {
+		o.Client = client
+	}
+}
+
+func WithProxy(proxyURL *url.URL) Option {
+	return func(o *Options) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+		o.Client = &http.Client{
+			Timeout:   defaultClient.Timeout,
+			Transport: transport,
+		}
+	}
+}
+
+func WithRetries(retries int) Option {
+	return func(o *Options) {
+		if retries <= 0 {
+			retries = NoRetries
+		}
+		o.Retries = retries
+	}
+}
+
+func WithExtraParam(key string, value string) Option {
+	return func(o *Options) {
+		if o.ExtraParams == nil {
+			o.ExtraParams = make(map[string]string)
+		}
+		o.ExtraParams[key] = value
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
@@ -1,12 +1,21 @@
 package ytsr
 
 import (
-	"encoding/json"
+	"encoding/base64"
 	"fmt"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/Victiniiiii/ytpl-ytsr-go/pkg/internal/textnum"
+)
+
+var (
+	VideoIDRegex    = regexp.MustCompile(`^[\w-]{11}$`)
+	PlaylistIDRegex = regexp.MustCompile(`^(PL|UU|LL|FL|RD|OL|UL|EL)[\w-]{10,}$`)
+	ChannelIDRegex  = regexp.MustCompile(`^UC[\w-]{22}$`)
 )
 
 func parseBody(body string, opts *Options) (*ParsedData, error) {
@@ -20,7 +29,18 @@ func parseBody(body string, opts *Options) (*ParsedData, error) {
 	var jsonData map[string]interface{}
 	var jsonStr string
 
+	if opts.ExtractInitialData != nil {
+		if custom, ok := opts.ExtractInitialData(body); ok {
+			if err := unmarshalJSON(opts, []byte(custom), &jsonData); err != nil {
+				jsonData = nil
+			}
+		}
+	}
+
 	for _, pattern := range patterns {
+		if jsonData != nil {
+			break
+		}
 		re := regexp.MustCompile(pattern)
 		match := re.FindStringSubmatch(body)
 		if len(match) > 1 {
@@ -28,7 +48,7 @@ func parseBody(body string, opts *Options) (*ParsedData, error) {
 			if strings.HasSuffix(pattern, "};") {
 				jsonStr += "}"
 			}
-			err := json.Unmarshal([]byte(jsonStr), &jsonData)
+			err := unmarshalJSON(opts, []byte(jsonStr), &jsonData)
 			if err == nil {
 				break
 			}
@@ -56,10 +76,8 @@ func parseResponse(parsed *ParsedData, opts *Options) (*SearchResult, error) {
 	}
 
 	var twoCol map[string]interface{}
-	if contents, ok := parsed.JSON["contents"].(map[string]interface{}); ok {
-		if tc, ok := contents["twoColumnSearchResultsRenderer"].(map[string]interface{}); ok {
-			twoCol = tc
-		}
+	if tc, ok := findContentsRenderer(parsed.JSON["contents"], "twoColumnSearchResultsRenderer"); ok {
+		twoCol = tc
 	}
 	if twoCol == nil {
 		if tc, ok := findTwoColumnSearchResultsRenderer(parsed.JSON); ok {
@@ -68,13 +86,11 @@ func parseResponse(parsed *ParsedData, opts *Options) (*SearchResult, error) {
 	}
 
 	if twoCol == nil {
-		if contents, ok := parsed.JSON["contents"].(map[string]interface{}); ok {
-			if sectionList, ok := contents["sectionListRenderer"].(map[string]interface{}); ok {
-				twoCol = map[string]interface{}{
-					"primaryContents": map[string]interface{}{
-						"sectionListRenderer": sectionList,
-					},
-				}
+		if sectionList, ok := findContentsRenderer(parsed.JSON["contents"], "sectionListRenderer"); ok {
+			twoCol = map[string]interface{}{
+				"primaryContents": map[string]interface{}{
+					"sectionListRenderer": sectionList,
+				},
 			}
 		}
 	}
@@ -88,30 +104,456 @@ func parseResponse(parsed *ParsedData, opts *Options) (*SearchResult, error) {
 		return nil, fmt.Errorf("invalid response format")
 	}
 
-	rawItems, _ := parseWrapper(primaryContents)
+	rawItems, continuation := parseWrapper(primaryContents)
 
-	for i, item := range rawItems {
-		if i >= opts.Limit {
-			break
+	if err := parseItems(rawItems, opts, result); err != nil {
+		return nil, err
+	}
+
+	if token := getContinuationToken(continuation); token != "" && !opts.stopped && !opts.truncated {
+		result.Continuation = token
+		result.Cursor = &SearchCursor{
+			Token:         token,
+			ClientVersion: contextClientVersion(parsed.Context),
+			Query:         opts.Query,
+			GL:            opts.GL,
+			HL:            opts.HL,
 		}
+	}
 
-		parsedItem := parseItem(item)
-		if parsedItem != nil && parsedItem.Type == opts.Type {
-			result.Items = append(result.Items, *parsedItem)
+	result.Results = parseEstimatedResults(parsed.JSON, twoCol)
+
+	if header, ok := parsed.JSON["header"].(map[string]interface{}); ok {
+		result.AppliedFilter = parseSelectedChip(header)
+	}
+
+	result.PartialDueToRegion = hasRegionNotice(primaryContents)
+	parseCorrection(primaryContents, result)
+
+	if opts.KeepRawJSON {
+		result.Raw = parsed.JSON
+	}
+
+	return result, nil
+}
+
+func parseEstimatedResults(jsonResp map[string]interface{}, twoCol map[string]interface{}) int {
+	if num, ok := estimatedResultsValue(jsonResp); ok {
+		return num
+	}
+	if num, ok := findEstimatedResults(jsonResp["responseContext"]); ok {
+		return num
+	}
+	if num, ok := findEstimatedResults(twoCol); ok {
+		return num
+	}
+	return 0
+}
+
+func estimatedResultsValue(m map[string]interface{}) (int, bool) {
+	switch v := m["estimatedResults"].(type) {
+	case string:
+		return textnum.Parse(v)
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+func findEstimatedResults(obj interface{}) (int, bool) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if num, ok := estimatedResultsValue(v); ok {
+			return num, true
 		}
+		for _, value := range v {
+			if num, ok := findEstimatedResults(value); ok {
+				return num, true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if num, ok := findEstimatedResults(value); ok {
+				return num, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func parseContinuationResponse(jsonResp map[string]interface{}, clientVersion string, opts *Options) (*SearchResult, error) {
+	result := &SearchResult{
+		Query: opts.Query,
+		Items: []SearchItem{},
 	}
 
-	if estimatedResults, ok := parsed.JSON["estimatedResults"]; ok {
-		if results, ok := estimatedResults.(string); ok {
-			if num, err := strconv.Atoi(results); err == nil {
-				result.Results = num
+	var continuationItems []interface{}
+	if commands, ok := jsonResp["onResponseReceivedCommands"].([]interface{}); ok {
+		for _, command := range commands {
+			if commandMap, ok := command.(map[string]interface{}); ok {
+				if action, ok := commandMap["appendContinuationItemsAction"].(map[string]interface{}); ok {
+					if items, ok := action["continuationItems"].([]interface{}); ok {
+						continuationItems = items
+						break
+					}
+				}
 			}
 		}
 	}
 
+	if continuationItems == nil {
+		return nil, fmt.Errorf("invalid continuation response format")
+	}
+
+	rawItems, continuation := parseWrapper(map[string]interface{}{
+		"sectionListRenderer": map[string]interface{}{
+			"contents": continuationItems,
+		},
+	})
+
+	if err := parseItems(rawItems, opts, result); err != nil {
+		return nil, err
+	}
+
+	if token := getContinuationToken(continuation); token != "" && !opts.stopped && !opts.truncated {
+		result.Continuation = token
+		result.Cursor = &SearchCursor{
+			Token:         token,
+			ClientVersion: clientVersion,
+			Query:         opts.Query,
+			GL:            opts.GL,
+			HL:            opts.HL,
+		}
+	}
+
+	result.Results = parseEstimatedResults(jsonResp, nil)
+
+	if opts.KeepRawJSON {
+		result.Raw = jsonResp
+	}
+
 	return result, nil
 }
 
+func parseItems(rawItems []interface{}, opts *Options, result *SearchResult) error {
+	opts.truncated = false
+	for _, item := range flattenShelves(rawItems) {
+		if opts.stopped {
+			break
+		}
+
+		if isCorrectionItem(item) {
+			continue
+		}
+
+		parsedItem := sanitizeItem(parseItem(item))
+		if parsedItem == nil {
+			if key := unknownRendererKey(item); key != "" {
+				result.Stats.SkippedUnknown++
+				if opts.StrictParsing {
+					return fmt.Errorf("%w: %s", ErrUnknownRenderer, key)
+				}
+			} else if !isContinuationItem(item) {
+				result.Stats.SkippedUnavailable++
+			}
+			continue
+		}
+
+		result.Stats.Parsed++
+		applyThumbnailOptions(parsedItem, opts)
+		if opts.LiveOnly && !parsedItem.IsLive {
+			continue
+		}
+		if parsedItem.Type != opts.Type {
+			continue
+		}
+		if opts.FilterFunc != nil && !opts.FilterFunc(*parsedItem) {
+			continue
+		}
+		if len(result.Items) >= opts.Limit {
+			opts.truncated = true
+			break
+		}
+		result.Items = append(result.Items, *parsedItem)
+		if opts.OnItem != nil && !opts.OnItem(*parsedItem) {
+			opts.stopped = true
+		}
+	}
+	return nil
+}
+
+func flattenShelves(rawItems []interface{}) []interface{} {
+	var flattened []interface{}
+	for _, item := range rawItems {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			flattened = append(flattened, item)
+			continue
+		}
+
+		if shelf, ok := itemMap["shelfRenderer"].(map[string]interface{}); ok {
+			if content, ok := shelf["content"].(map[string]interface{}); ok {
+				for _, list := range content {
+					if listMap, ok := list.(map[string]interface{}); ok {
+						if items, ok := listMap["items"].([]interface{}); ok {
+							flattened = append(flattened, flattenShelves(items)...)
+						}
+					}
+				}
+			}
+			continue
+		}
+
+		if shelf, ok := itemMap["reelShelfRenderer"].(map[string]interface{}); ok {
+			if items, ok := shelf["items"].([]interface{}); ok {
+				flattened = append(flattened, flattenShelves(items)...)
+			}
+			continue
+		}
+
+		if shelf, ok := itemMap["gridShelfViewModel"].(map[string]interface{}); ok {
+			if contents, ok := shelf["contents"].([]interface{}); ok {
+				flattened = append(flattened, flattenShelves(contents)...)
+			}
+			continue
+		}
+
+		flattened = append(flattened, item)
+	}
+	return flattened
+}
+
+func applyThumbnailOptions(item *SearchItem, opts *Options) {
+	if opts.PreferredThumbnailWidth > 0 && len(item.Thumbnails) > 0 {
+		best := item.Thumbnails[0]
+		for _, thumbnail := range item.Thumbnails[1:] {
+			if absInt(thumbnail.Width-opts.PreferredThumbnailWidth) < absInt(best.Width-opts.PreferredThumbnailWidth) {
+				best = thumbnail
+			}
+		}
+		item.Thumbnail = best.URL
+	}
+
+	if opts.OmitThumbnails {
+		item.Thumbnails = nil
+		item.MovingThumbnails = nil
+	}
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sanitizeItem(item *SearchItem) *SearchItem {
+	if item == nil {
+		return nil
+	}
+
+	item.ID = canonicalID(item.ID)
+	switch item.Type {
+	case "video", "movie":
+		if !VideoIDRegex.MatchString(item.ID) {
+			return nil
+		}
+		item.URL = BaseVideoURL + item.ID
+	case "short":
+		if !VideoIDRegex.MatchString(item.ID) {
+			return nil
+		}
+		item.URL = BaseShortsURL + item.ID
+	case "channel":
+		if !ChannelIDRegex.MatchString(item.ID) {
+			return nil
+		}
+	case "playlist":
+		if !PlaylistIDRegex.MatchString(item.ID) {
+			return nil
+		}
+		item.URL = "https://www.youtube.com/playlist?list=" + item.ID
+	}
+
+	if item.Author != nil {
+		item.Author.ChannelID = canonicalID(item.Author.ChannelID)
+		if !ChannelIDRegex.MatchString(item.Author.ChannelID) {
+			item.Author.ChannelID = ""
+		}
+	}
+
+	if item.Owner != nil {
+		item.Owner.ChannelID = canonicalID(item.Owner.ChannelID)
+		if !ChannelIDRegex.MatchString(item.Owner.ChannelID) {
+			item.Owner.ChannelID = ""
+		}
+	}
+
+	return item
+}
+
+func canonicalID(id string) string {
+	if i := strings.IndexAny(id, "&?#"); i != -1 {
+		id = id[:i]
+	}
+	return strings.TrimSpace(id)
+}
+
+func IsPlausibleToken(token string) bool {
+	token = strings.TrimRight(strings.ReplaceAll(token, "%3D", "="), "=")
+	if len(token) < minTokenLength {
+		return false
+	}
+
+	token = strings.NewReplacer("+", "-", "/", "_").Replace(token)
+	_, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil
+}
+
+func getContinuationToken(continuation interface{}) string {
+	item, ok := continuation.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	if renderer, ok := item["continuationItemRenderer"].(map[string]interface{}); ok {
+		if endpoint, ok := renderer["continuationEndpoint"].(map[string]interface{}); ok {
+			if command, ok := endpoint["continuationCommand"].(map[string]interface{}); ok {
+				if token, ok := command["token"].(string); ok && IsPlausibleToken(token) {
+					return token
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func contextClientVersion(context *Context) string {
+	if context == nil || context.Client == nil {
+		return ""
+	}
+	clientVersion, _ := context.Client["clientVersion"].(string)
+	return clientVersion
+}
+
+var noticeRenderers = []string{"alertRenderer", "alertWithButtonRenderer", "messageRenderer", "backgroundPromoRenderer"}
+
+var correctionRenderers = []string{"showingResultsForRenderer", "didYouMeanRenderer"}
+
+func isCorrectionItem(item interface{}) bool {
+	itemMap, ok := item.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for _, key := range correctionRenderers {
+		if _, ok := itemMap[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCorrection(obj interface{}, result *SearchResult) bool {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for _, key := range correctionRenderers {
+			if renderer, ok := v[key].(map[string]interface{}); ok {
+				corrected := parseText(renderer["correctedQuery"])
+				if corrected == "" {
+					continue
+				}
+				result.CorrectedQuery = corrected
+				result.OriginalQuery = parseText(renderer["originalQuery"])
+				if result.OriginalQuery == "" {
+					result.OriginalQuery = result.Query
+				}
+				return true
+			}
+		}
+		for _, value := range v {
+			if parseCorrection(value, result) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if parseCorrection(item, result) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasRegionNotice(obj interface{}) bool {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for _, key := range noticeRenderers {
+			if renderer, ok := v[key].(map[string]interface{}); ok {
+				for _, field := range []string{"text", "title", "bodyText"} {
+					text := strings.ToLower(parseText(renderer[field]))
+					if strings.Contains(text, "country") || strings.Contains(text, "region") {
+						return true
+					}
+				}
+			}
+		}
+		for _, value := range v {
+			if hasRegionNotice(value) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if hasRegionNotice(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func findContentsRenderer(contents interface{}, key string) (map[string]interface{}, bool) {
+	switch v := contents.(type) {
+	case map[string]interface{}:
+		renderer, ok := v[key].(map[string]interface{})
+		return renderer, ok
+	case []interface{}:
+		for _, item := range v {
+			if itemMap, ok := item.(map[string]interface{}); ok {
+				if renderer, ok := itemMap[key].(map[string]interface{}); ok {
+					return renderer, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+func parseSelectedChip(obj interface{}) string {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if chip, ok := v["chipCloudChipRenderer"].(map[string]interface{}); ok {
+			if selected, ok := chip["isSelected"].(bool); ok && selected {
+				return parseText(chip["text"])
+			}
+			return ""
+		}
+		for _, value := range v {
+			if label := parseSelectedChip(value); label != "" {
+				return label
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if label := parseSelectedChip(item); label != "" {
+				return label
+			}
+		}
+	}
+	return ""
+}
+
 func parseWrapper(primaryContents map[string]interface{}) ([]interface{}, interface{}) {
 	var rawItems []interface{}
 	var continuation interface{}
@@ -121,9 +563,8 @@ func parseWrapper(primaryContents map[string]interface{}) ([]interface{}, interf
 			for _, content := range contents {
 				if contentMap, ok := content.(map[string]interface{}); ok {
 					if itemSection, ok := contentMap["itemSectionRenderer"].(map[string]interface{}); ok {
-						if items, ok := itemSection["contents"].([]interface{}); ok {
+						if items, ok := itemSection["contents"].([]interface{}); ok && rawItems == nil {
 							rawItems = items
-							break
 						}
 					}
 					if _, ok := contentMap["continuationItemRenderer"]; ok {
@@ -144,27 +585,78 @@ func parseItem(item interface{}) *SearchItem {
 	}
 
 	for key, value := range itemMap {
+		renderer, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
 		switch key {
 		case "videoRenderer":
-			return parseVideo(value.(map[string]interface{}))
+			return parseVideo(renderer)
 		case "playlistRenderer":
-			return parsePlaylist(value.(map[string]interface{}))
+			return parsePlaylist(renderer)
 		case "gridVideoRenderer":
-			return parseVideo(value.(map[string]interface{}))
+			return parseVideo(renderer)
 		case "channelRenderer":
-			return nil
+			return parseChannel(renderer)
 		case "lockupViewModel":
-			return parseLockupViewModel(value.(map[string]interface{}))
-		case "gridShelfViewModel":
-			return nil
+			return parseLockupViewModel(renderer)
+		case "reelItemRenderer":
+			return parseShort(renderer)
+		case "shortsLockupViewModel":
+			return parseShortsLockupViewModel(renderer)
+		case "movieRenderer":
+			return parseMovie(renderer)
 		}
 	}
 
 	return nil
 }
 
+var knownRenderers = map[string]bool{
+	"videoRenderer":            true,
+	"playlistRenderer":         true,
+	"gridVideoRenderer":        true,
+	"channelRenderer":          true,
+	"lockupViewModel":          true,
+	"continuationItemRenderer": true,
+	"reelItemRenderer":         true,
+	"shortsLockupViewModel":    true,
+	"movieRenderer":            true,
+}
+
+func unknownRendererKey(item interface{}) string {
+	itemMap, ok := item.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	var unknown string
+	for key := range itemMap {
+		if knownRenderers[key] {
+			return ""
+		}
+		unknown = key
+	}
+	return unknown
+}
+
+func isContinuationItem(item interface{}) bool {
+	itemMap, ok := item.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = itemMap["continuationItemRenderer"]
+	return ok
+}
+
 func parseLockupViewModel(obj map[string]interface{}) *SearchItem {
-	if contentType, ok := obj["contentType"].(string); ok && contentType == "LOCKUP_CONTENT_TYPE_PLAYLIST" {
+	contentType, _ := obj["contentType"].(string)
+	if contentType == "LOCKUP_CONTENT_TYPE_VIDEO" {
+		return parseLockupVideo(obj)
+	}
+
+	if contentType == "LOCKUP_CONTENT_TYPE_PLAYLIST" {
 		item := &SearchItem{
 			Type: "playlist",
 		}
@@ -182,12 +674,177 @@ func parseLockupViewModel(obj map[string]interface{}) *SearchItem {
 			}
 		}
 
+		for _, text := range findBadgeTexts(obj["contentImage"]) {
+			if strings.Contains(strings.ToLower(text), "video") {
+				item.Length, _ = textnum.Parse(text)
+				break
+			}
+		}
+
 		return item
 	}
 
 	return nil
 }
 
+func parseUpcoming(obj map[string]interface{}, item *SearchItem) {
+	if upcoming, ok := obj["upcomingEventData"].(map[string]interface{}); ok {
+		item.IsUpcoming = true
+		if startTime, ok := upcoming["startTime"].(string); ok {
+			if seconds, err := strconv.ParseInt(startTime, 10, 64); err == nil {
+				item.ScheduledStart = time.Unix(seconds, 0)
+			}
+		}
+	}
+
+	if overlays, ok := obj["thumbnailOverlays"].([]interface{}); ok {
+		for _, overlay := range overlays {
+			overlayMap, _ := overlay.(map[string]interface{})
+			if timeStatus, ok := overlayMap["thumbnailOverlayTimeStatusRenderer"].(map[string]interface{}); ok {
+				if style, ok := timeStatus["style"].(string); ok && style == "UPCOMING" {
+					item.IsUpcoming = true
+				}
+			}
+		}
+	}
+
+	if item.IsLive {
+		item.IsUpcoming = false
+	}
+}
+
+func findBadgeTexts(obj interface{}) []string {
+	var texts []string
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if badge, ok := v["thumbnailBadgeViewModel"].(map[string]interface{}); ok {
+			if text, ok := badge["text"].(string); ok {
+				texts = append(texts, text)
+			}
+		}
+		for _, value := range v {
+			texts = append(texts, findBadgeTexts(value)...)
+		}
+	case []interface{}:
+		for _, value := range v {
+			texts = append(texts, findBadgeTexts(value)...)
+		}
+	}
+	return texts
+}
+
+func parseBadges(badges []interface{}, item *SearchItem) {
+	for _, badge := range badges {
+		badgeMap, ok := badge.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if renderer, ok := badgeMap["metadataBadgeRenderer"].(map[string]interface{}); ok {
+			if label, ok := renderer["label"].(string); ok && !containsString(item.Badges, label) {
+				item.Badges = append(item.Badges, label)
+			}
+			if style, ok := renderer["style"].(string); ok && style == "BADGE_STYLE_TYPE_MEMBERS_ONLY" {
+				item.IsMembersOnly = true
+			}
+		} else if viewModel, ok := badgeMap["thumbnailBadgeViewModel"].(map[string]interface{}); ok {
+			if text, ok := viewModel["text"].(string); ok && !containsString(item.Badges, text) {
+				item.Badges = append(item.Badges, text)
+			}
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func parseLockupVideo(obj map[string]interface{}) *SearchItem {
+	item := &SearchItem{
+		Type: "video",
+	}
+
+	if contentId, ok := obj["contentId"].(string); ok {
+		item.ID = contentId
+		item.URL = BaseVideoURL + contentId
+	}
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		if lockupMetadata, ok := metadata["lockupMetadataViewModel"].(map[string]interface{}); ok {
+			item.Name = parseText(lockupMetadata["title"])
+		}
+	}
+
+	contentImage, _ := obj["contentImage"].(map[string]interface{})
+	thumbnailViewModel, ok := contentImage["thumbnailViewModel"].(map[string]interface{})
+	if !ok {
+		return item
+	}
+
+	if image, ok := thumbnailViewModel["image"].(map[string]interface{}); ok {
+		if sources, ok := image["sources"].([]interface{}); ok {
+			item.Thumbnails = prepareThumbnails(sources)
+			if len(item.Thumbnails) > 0 {
+				item.Thumbnail = item.Thumbnails[0].URL
+			}
+		}
+	}
+
+	if overlays, ok := thumbnailViewModel["overlays"].([]interface{}); ok {
+		for _, overlay := range overlays {
+			overlayMap, _ := overlay.(map[string]interface{})
+			for _, key := range []string{"thumbnailOverlayBadgeViewModel", "thumbnailBottomOverlayViewModel"} {
+				badgeOverlay, ok := overlayMap[key].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				badges, _ := badgeOverlay["thumbnailBadges"].([]interface{})
+				if more, ok := badgeOverlay["badges"].([]interface{}); ok {
+					badges = append(badges, more...)
+				}
+				for _, badge := range badges {
+					badgeMap, _ := badge.(map[string]interface{})
+					badgeViewModel, ok := badgeMap["thumbnailBadgeViewModel"].(map[string]interface{})
+					if !ok {
+						continue
+					}
+					text, _ := badgeViewModel["text"].(string)
+					if strings.EqualFold(text, "LIVE") {
+						item.IsLive = true
+					} else if item.Duration == "" && strings.Contains(text, ":") {
+						item.Duration = text
+					}
+				}
+			}
+		}
+	}
+
+	parseDurationSeconds(obj, item)
+
+	return item
+}
+
+func parseDurationSeconds(obj map[string]interface{}, item *SearchItem) {
+	if item.IsLive {
+		return
+	}
+
+	switch lengthSeconds := obj["lengthSeconds"].(type) {
+	case string:
+		item.DurationSeconds, _ = strconv.Atoi(lengthSeconds)
+	case float64:
+		item.DurationSeconds = int(lengthSeconds)
+	}
+	if item.DurationSeconds == 0 {
+		item.DurationSeconds, _ = textnum.ParseDuration(item.Duration)
+	}
+}
+
 func parseVideo(obj map[string]interface{}) *SearchItem {
 	item := &SearchItem{
 		Type: "video",
@@ -211,6 +868,16 @@ func parseVideo(obj map[string]interface{}) *SearchItem {
 		}
 	}
 
+	if richThumbnail, ok := obj["richThumbnail"].(map[string]interface{}); ok {
+		if moving, ok := richThumbnail["movingThumbnailRenderer"].(map[string]interface{}); ok {
+			if details, ok := moving["movingThumbnailDetails"].(map[string]interface{}); ok {
+				if thumbnails, ok := details["thumbnails"].([]interface{}); ok {
+					item.MovingThumbnails = prepareThumbnails(thumbnails)
+				}
+			}
+		}
+	}
+
 	if desc, ok := obj["descriptionSnippet"]; ok {
 		item.Description = parseText(desc)
 	} else if detailedSnippets, ok := obj["detailedMetadataSnippets"].([]interface{}); ok && len(detailedSnippets) > 0 {
@@ -219,8 +886,8 @@ func parseVideo(obj map[string]interface{}) *SearchItem {
 				item.Description = parseText(snippetText)
 			}
 		}
-	} else if richSnippet, ok := obj["richSnippet"]; ok {
-		if snippetText, ok := richSnippet.(map[string]interface{})["snippetText"]; ok {
+	} else if richSnippet, ok := obj["richSnippet"].(map[string]interface{}); ok {
+		if snippetText, ok := richSnippet["snippetText"]; ok {
 			item.Description = parseText(snippetText)
 		}
 	}
@@ -242,11 +909,17 @@ func parseVideo(obj map[string]interface{}) *SearchItem {
 	item.Author = parseAuthor(obj)
 
 	if badges, ok := obj["badges"].([]interface{}); ok {
-		for _, badge := range badges {
-			if badgeMap, ok := badge.(map[string]interface{}); ok {
-				if renderer, ok := badgeMap["metadataBadgeRenderer"].(map[string]interface{}); ok {
-					if label, ok := renderer["label"].(string); ok {
-						item.Badges = append(item.Badges, label)
+		parseBadges(badges, item)
+	}
+
+	if overlays, ok := obj["thumbnailOverlays"].([]interface{}); ok {
+		for _, overlay := range overlays {
+			if overlayMap, ok := overlay.(map[string]interface{}); ok {
+				for _, value := range overlayMap {
+					if renderer, ok := value.(map[string]interface{}); ok {
+						if badges, ok := renderer["badges"].([]interface{}); ok {
+							parseBadges(badges, item)
+						}
 					}
 				}
 			}
@@ -254,9 +927,257 @@ func parseVideo(obj map[string]interface{}) *SearchItem {
 	}
 
 	for _, badge := range item.Badges {
-		if badge == "LIVE NOW" || badge == "LIVE" {
+		switch strings.ToLower(badge) {
+		case "live now", "live":
 			item.IsLive = true
-			break
+		case "members only":
+			item.IsMembersOnly = true
+		case "cc":
+			item.HasCaptions = true
+		case "4k", "8k":
+			item.Is4K = true
+		}
+	}
+
+	parseUpcoming(obj, item)
+	parseDurationSeconds(obj, item)
+
+	return item
+}
+
+func parseMovie(obj map[string]interface{}) *SearchItem {
+	item := &SearchItem{
+		Type: "movie",
+	}
+
+	if videoId, ok := obj["videoId"].(string); ok {
+		item.ID = videoId
+		item.URL = BaseVideoURL + videoId
+	}
+
+	item.Name = parseText(obj["title"])
+	item.Duration = parseText(obj["lengthText"])
+	item.Description = parseText(obj["descriptionSnippet"])
+
+	if thumbnail, ok := obj["thumbnail"].(map[string]interface{}); ok {
+		if thumbnails, ok := thumbnail["thumbnails"].([]interface{}); ok {
+			item.Thumbnails = prepareThumbnails(thumbnails)
+			if len(item.Thumbnails) > 0 {
+				item.Thumbnail = item.Thumbnails[0].URL
+			}
+		}
+	}
+
+	if metadataItems, ok := obj["bottomMetadataItems"].([]interface{}); ok && len(metadataItems) > 0 {
+		for _, part := range strings.Split(parseText(metadataItems[0]), "•") {
+			part = strings.TrimSpace(part)
+			if year, err := strconv.Atoi(part); err == nil && len(part) == 4 {
+				item.Year = year
+			} else if part != "" && item.Genre == "" {
+				item.Genre = part
+			}
+		}
+	}
+
+	item.Author = parseAuthor(obj)
+	parseDurationSeconds(obj, item)
+
+	return item
+}
+
+func parseShort(obj map[string]interface{}) *SearchItem {
+	item := &SearchItem{
+		Type: "short",
+	}
+
+	if videoId, ok := obj["videoId"].(string); ok {
+		item.ID = videoId
+		item.URL = BaseShortsURL + videoId
+	}
+
+	if headline, ok := obj["headline"]; ok {
+		item.Name = parseText(headline)
+	} else if title, ok := obj["title"]; ok {
+		item.Name = parseText(title)
+	}
+
+	if thumbnail, ok := obj["thumbnail"].(map[string]interface{}); ok {
+		if thumbnails, ok := thumbnail["thumbnails"].([]interface{}); ok {
+			item.Thumbnails = prepareThumbnails(thumbnails)
+			if len(item.Thumbnails) > 0 {
+				item.Thumbnail = item.Thumbnails[0].URL
+			}
+		}
+	}
+
+	if viewCount, ok := obj["viewCountText"]; ok {
+		if views := parseIntegerFromText(viewCount); views > 0 {
+			item.Views = &views
+		}
+	}
+
+	return item
+}
+
+func parseChannel(obj map[string]interface{}) *SearchItem {
+	item := &SearchItem{
+		Type: "channel",
+	}
+
+	author := &Author{}
+
+	if channelId, ok := obj["channelId"].(string); ok {
+		item.ID = channelId
+		item.URL = BaseURL + "channel/" + channelId
+		author.ChannelID = channelId
+		author.URL = item.URL
+	}
+
+	if title, ok := obj["title"]; ok {
+		item.Name = parseText(title)
+		author.Name = item.Name
+	}
+
+	if navEndpoint, ok := obj["navigationEndpoint"].(map[string]interface{}); ok {
+		if browseEndpoint, ok := navEndpoint["browseEndpoint"].(map[string]interface{}); ok {
+			if canonicalUrl, ok := browseEndpoint["canonicalBaseUrl"].(string); ok {
+				if u, err := url.Parse(BaseURL); err == nil {
+					if fullUrl, err := u.Parse(canonicalUrl); err == nil {
+						author.URL = fullUrl.String()
+					}
+				}
+			}
+		}
+	}
+
+	if thumbnail, ok := obj["thumbnail"].(map[string]interface{}); ok {
+		if thumbnails, ok := thumbnail["thumbnails"].([]interface{}); ok {
+			item.Thumbnails = prepareThumbnails(thumbnails)
+			if len(item.Thumbnails) > 0 {
+				item.Thumbnail = item.Thumbnails[0].URL
+			}
+			author.Avatars = item.Thumbnails
+			if len(author.Avatars) > 0 {
+				author.BestAvatar = &author.Avatars[0]
+			}
+		}
+	}
+
+	author.Subscribers = parseSubscribers(obj)
+
+	item.Description = parseText(obj["descriptionSnippet"])
+	item.Links = parseLinks(obj["descriptionSnippet"])
+
+	if ownerBadges, ok := obj["ownerBadges"].([]interface{}); ok {
+		for _, badge := range ownerBadges {
+			if badgeMap, ok := badge.(map[string]interface{}); ok {
+				if renderer, ok := badgeMap["metadataBadgeRenderer"].(map[string]interface{}); ok {
+					if tooltip, ok := renderer["tooltip"].(string); ok {
+						author.Badges = append(author.Badges, tooltip)
+						if strings.Contains(strings.ToUpper(tooltip), "VERIFIED") || strings.Contains(strings.ToUpper(tooltip), "OFFICIAL") {
+							author.Verified = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	item.Author = author
+
+	return item
+}
+
+func parseLinks(text interface{}) []string {
+	textMap, ok := text.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	runs, ok := textMap["runs"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var links []string
+	for _, run := range runs {
+		runMap, _ := run.(map[string]interface{})
+		navEndpoint, ok := runMap["navigationEndpoint"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		link := ""
+		if urlEndpoint, ok := navEndpoint["urlEndpoint"].(map[string]interface{}); ok {
+			link, _ = urlEndpoint["url"].(string)
+		} else if commandMetadata, ok := navEndpoint["commandMetadata"].(map[string]interface{}); ok {
+			webCommand, _ := commandMetadata["webCommandMetadata"].(map[string]interface{})
+			link, _ = webCommand["url"].(string)
+		}
+		if link == "" {
+			continue
+		}
+
+		if u, err := url.Parse(BaseURL); err == nil {
+			if full, err := u.Parse(link); err == nil {
+				link = full.String()
+				if full.Path == "/redirect" && full.Query().Get("q") != "" {
+					link = full.Query().Get("q")
+				}
+			}
+		}
+		links = append(links, link)
+	}
+
+	return links
+}
+
+func parseSubscribers(obj map[string]interface{}) *int {
+	for _, key := range []string{"subscriberCountText", "videoCountText"} {
+		text := parseText(obj[key])
+		if !strings.Contains(strings.ToLower(text), "subscriber") {
+			continue
+		}
+		if subscribers, ok := textnum.Parse(text); ok {
+			return &subscribers
+		}
+	}
+	return nil
+}
+
+func parseShortsLockupViewModel(obj map[string]interface{}) *SearchItem {
+	item := &SearchItem{
+		Type: "short",
+	}
+
+	if onTap, ok := obj["onTap"].(map[string]interface{}); ok {
+		if command, ok := onTap["innertubeCommand"].(map[string]interface{}); ok {
+			if endpoint, ok := command["reelWatchEndpoint"].(map[string]interface{}); ok {
+				if videoId, ok := endpoint["videoId"].(string); ok {
+					item.ID = videoId
+				}
+			}
+		}
+	}
+	if item.ID == "" {
+		if entityId, ok := obj["entityId"].(string); ok {
+			item.ID = strings.TrimPrefix(entityId, "shorts-shelf-item-")
+		}
+	}
+	item.URL = BaseShortsURL + item.ID
+
+	if overlay, ok := obj["overlayMetadata"].(map[string]interface{}); ok {
+		item.Name = parseText(overlay["primaryText"])
+		if views := parseIntegerFromText(overlay["secondaryText"]); views > 0 {
+			item.Views = &views
+		}
+	}
+
+	if thumbnail, ok := obj["thumbnail"].(map[string]interface{}); ok {
+		if sources, ok := thumbnail["sources"].([]interface{}); ok {
+			item.Thumbnails = prepareThumbnails(sources)
+			if len(item.Thumbnails) > 0 {
+				item.Thumbnail = item.Thumbnails[0].URL
+			}
 		}
 	}
 
@@ -277,6 +1198,17 @@ func parsePlaylist(obj map[string]interface{}) *SearchItem {
 		item.Name = parseText(title)
 	}
 
+	if videoCount, ok := obj["videoCount"].(string); ok {
+		item.Length, _ = textnum.Parse(videoCount)
+	}
+	if item.Length == 0 {
+		item.Length = parseIntegerFromText(obj["videoCountText"])
+	}
+
+	if publishedTime, ok := obj["publishedTimeText"]; ok {
+		item.UploadedAt = parseText(publishedTime)
+	}
+
 	item.Owner = parseOwner(obj)
 
 	return item
@@ -292,6 +1224,8 @@ func parseAuthor(obj map[string]interface{}) *Author {
 					author.Name = text
 				}
 
+				author.Subscribers = parseSubscribers(obj)
+
 				if navEndpoint, ok := run["navigationEndpoint"].(map[string]interface{}); ok {
 					if browseEndpoint, ok := navEndpoint["browseEndpoint"].(map[string]interface{}); ok {
 						if browseId, ok := browseEndpoint["browseId"].(string); ok {
@@ -307,17 +1241,9 @@ func parseAuthor(obj map[string]interface{}) *Author {
 					}
 				}
 
-				if ctsr, ok := obj["channelThumbnailSupportedRenderers"].(map[string]interface{}); ok {
-					if renderer, ok := ctsr["channelThumbnailWithLinkRenderer"].(map[string]interface{}); ok {
-						if thumbnail, ok := renderer["thumbnail"].(map[string]interface{}); ok {
-							if thumbnails, ok := thumbnail["thumbnails"].([]interface{}); ok {
-								author.Avatars = prepareThumbnails(thumbnails)
-								if len(author.Avatars) > 0 {
-									author.BestAvatar = &author.Avatars[0]
-								}
-							}
-						}
-					}
+				author.Avatars = parseChannelAvatars(obj)
+				if len(author.Avatars) > 0 {
+					author.BestAvatar = &author.Avatars[0]
 				}
 
 				if ownerBadges, ok := obj["ownerBadges"].([]interface{}); ok {
@@ -393,7 +1319,6 @@ func parseOwner(obj map[string]interface{}) *Owner {
 								owner.Verified = true
 							}
 						}
-					} else if renderer, ok := badgeMap["metadataBadgeRenderer"].(map[string]interface{}); ok {
 						if style, ok := renderer["style"].(string); ok {
 							if style == "BADGE_STYLE_TYPE_VERIFIED" || style == "BADGE_STYLE_TYPE_VERIFIED_ARTIST" {
 								owner.Verified = true
@@ -404,12 +1329,35 @@ func parseOwner(obj map[string]interface{}) *Owner {
 			}
 		}
 
+		owner.Avatars = parseChannelAvatars(obj)
+		if len(owner.Avatars) > 0 {
+			owner.BestAvatar = &owner.Avatars[0]
+		}
+
 		return owner
 	}
 
 	return nil
 }
 
+func parseChannelAvatars(obj map[string]interface{}) []Thumbnail {
+	if ctsr, ok := obj["channelThumbnailSupportedRenderers"].(map[string]interface{}); ok {
+		if renderer, ok := ctsr["channelThumbnailWithLinkRenderer"].(map[string]interface{}); ok {
+			if thumbnail, ok := renderer["thumbnail"].(map[string]interface{}); ok {
+				if thumbnails, ok := thumbnail["thumbnails"].([]interface{}); ok {
+					return prepareThumbnails(thumbnails)
+				}
+			}
+		}
+	}
+	if thumbnail, ok := obj["channelThumbnail"].(map[string]interface{}); ok {
+		if thumbnails, ok := thumbnail["thumbnails"].([]interface{}); ok {
+			return prepareThumbnails(thumbnails)
+		}
+	}
+	return nil
+}
+
 func parseText(text interface{}) string {
 	if text == nil {
 		return ""
@@ -441,16 +1389,6 @@ func parseText(text interface{}) string {
 }
 
 func parseIntegerFromText(text interface{}) int {
-	textStr := parseText(text)
-	if textStr == "" {
-		return 0
-	}
-
-	re := regexp.MustCompile(`\D+`)
-	numStr := re.ReplaceAllString(textStr, "")
-
-	if num, err := strconv.Atoi(numStr); err == nil {
-		return num
-	}
-	return 0
+	num, _ := textnum.Parse(parseText(text))
+	return num
 }
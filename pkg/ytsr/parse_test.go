@@ -0,0 +1,389 @@
+package ytsr
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func parseFixture(t *testing.T, data string, opts *Options) (*SearchResult, error) {
+	t.Helper()
+
+	var jsonData map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &jsonData); err != nil {
+		t.Fatal(err)
+	}
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	return parseResponse(&ParsedData{JSON: jsonData}, checkArgs("lofi", opts))
+}
+
+func parseItemJSON(t *testing.T, data string) *SearchItem {
+	t.Helper()
+
+	var item interface{}
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		t.Fatal(err)
+	}
+	return sanitizeItem(parseItem(item))
+}
+
+func TestStrictParsing(t *testing.T) {
+	data := strings.Replace(string(readFixture(t, "search.json")), `{"videoRenderer":{"videoId":"srch0000003"`, `{"adSlotRenderer":{"videoId":"srch0000003"`, 1)
+
+	opts := DefaultOptions()
+	opts.Limit = 20
+	result, err := parseFixture(t, data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 19 || result.Stats.SkippedUnknown != 1 {
+		t.Errorf("got %d items and %d unknown renderers, want 19 and 1", len(result.Items), result.Stats.SkippedUnknown)
+	}
+
+	opts.StrictParsing = true
+	_, err = parseFixture(t, data, opts)
+	if !errors.Is(err, ErrUnknownRenderer) || !strings.Contains(err.Error(), "adSlotRenderer") {
+		t.Errorf("got %v, want ErrUnknownRenderer naming adSlotRenderer", err)
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	data := string(readFixture(t, "search.json"))
+	data = strings.Replace(data, `{"videoRenderer":{"videoId":"srch0000003"`, `{"adSlotRenderer":{"videoId":"srch0000003"`, 1)
+	data = strings.Replace(data, `"videoId":"srch0000005"`, `"videoId":"bad"`, 1)
+
+	opts := DefaultOptions()
+	opts.Limit = 20
+	result, err := parseFixture(t, data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ParseStats{Parsed: 18, SkippedUnknown: 1, SkippedUnavailable: 1}
+	if result.Stats != want {
+		t.Errorf("Stats = %+v, want %+v", result.Stats, want)
+	}
+	if len(result.Items) != 18 {
+		t.Errorf("got %d items, want 18", len(result.Items))
+	}
+}
+
+func TestCanonicalIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantID  string
+		wantURL string
+	}{
+		{"video", `{"videoRenderer":{"videoId":"dQw4w9WgXcQ"}}`, "dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"video with tracking suffix", `{"videoRenderer":{"videoId":"dQw4w9WgXcQ&pp=ygUEbG9maQ%3D%3D"}}`, "dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"short video id", `{"videoRenderer":{"videoId":"dQw4w9"}}`, "", ""},
+		{"playlist with tracking suffix", `{"playlistRenderer":{"playlistId":"PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP?si=abc"}}`, "PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP", "https://www.youtube.com/playlist?list=PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP"},
+		{"playlist with unknown prefix", `{"playlistRenderer":{"playlistId":"XXbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP"}}`, "", ""},
+		{"channel", `{"channelRenderer":{"channelId":"UCuAXFkgsw1L7xaCfnd5JJOw"}}`, "UCuAXFkgsw1L7xaCfnd5JJOw", "https://www.youtube.com/channel/UCuAXFkgsw1L7xaCfnd5JJOw"},
+		{"channel with wrong prefix", `{"channelRenderer":{"channelId":"HCuAXFkgsw1L7xaCfnd5JJOw"}}`, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := parseItemJSON(t, tt.data)
+			if tt.wantID == "" {
+				if item != nil {
+					t.Fatalf("got %+v, want the item skipped", item)
+				}
+				return
+			}
+			if item == nil {
+				t.Fatal("item was skipped")
+			}
+			if item.ID != tt.wantID || item.URL != tt.wantURL {
+				t.Errorf("got ID %q URL %q, want %q %q", item.ID, item.URL, tt.wantID, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestCanonicalAuthorID(t *testing.T) {
+	item := parseItemJSON(t, `{"videoRenderer":{"videoId":"dQw4w9WgXcQ","ownerText":{"runs":[{"text":"Owner","navigationEndpoint":{"browseEndpoint":{"browseId":"not-a-channel"}}}]}}}`)
+	if item == nil || item.Author == nil {
+		t.Fatalf("got %+v", item)
+	}
+	if item.Author.ChannelID != "" {
+		t.Errorf("Author.ChannelID = %q, want it cleared", item.Author.ChannelID)
+	}
+}
+
+func TestParseItemMalformedRenderers(t *testing.T) {
+	for _, data := range []string{
+		`{"videoRenderer":null}`,
+		`{"videoRenderer":"dQw4w9WgXcQ"}`,
+		`{"playlistRenderer":[]}`,
+		`{"gridVideoRenderer":42}`,
+		`{"lockupViewModel":true}`,
+		`{"lockupViewModel":{"contentType":"LOCKUP_CONTENT_TYPE_VIDEO","contentImage":"x","metadata":[]}}`,
+		`{"channelRenderer":{"channelId":"UCuAXFkgsw1L7xaCfnd5JJOw","ownerBadges":[null,"x",{"metadataBadgeRenderer":7}]}}`,
+		`{"videoRenderer":{"videoId":"dQw4w9WgXcQ","thumbnail":{"thumbnails":[null,"x"]},"ownerText":{"runs":[null]},"thumbnailOverlays":[1,{"x":null}]}}`,
+		`["videoRenderer"]`,
+		`null`,
+	} {
+		t.Run(data, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("parseItem panicked: %v", r)
+				}
+			}()
+			parseItemJSON(t, data)
+		})
+	}
+}
+
+func TestParseShelves(t *testing.T) {
+	video := func(id string) string {
+		return `{"videoRenderer":{"videoId":"` + id + `","title":{"runs":[{"text":"` + id + `"}]}}}`
+	}
+	data := `{"contents":{"twoColumnSearchResultsRenderer":{"primaryContents":{"sectionListRenderer":{"contents":[{"itemSectionRenderer":{"contents":[` +
+		video("shelf000000") + `,` +
+		`{"shelfRenderer":{"title":{"simpleText":"People also watched"},"content":{"verticalListRenderer":{"items":[` +
+		video("shelf000001") + `,` + video("shelf000002") + `,` + video("shelf000003") + `]}}}},` +
+		`{"gridShelfViewModel":{"contents":[` + video("shelf000004") + `]}}` +
+		`]}}]}}}}}`
+
+	opts := DefaultOptions()
+	opts.Limit = 20
+	result, err := parseFixture(t, data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, item := range result.Items {
+		ids = append(ids, item.ID)
+	}
+	want := []string{"shelf000000", "shelf000001", "shelf000002", "shelf000003", "shelf000004"}
+	if strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", ids, want)
+	}
+}
+
+func TestParseSubscribers(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{"channel", `{"channelRenderer":{"channelId":"UCuAXFkgsw1L7xaCfnd5JJOw","subscriberCountText":{"simpleText":"1.2M subscribers"}}}`, 1200000},
+		{"channel with handle layout", `{"channelRenderer":{"channelId":"UCuAXFkgsw1L7xaCfnd5JJOw","subscriberCountText":{"simpleText":"@lofigirl"},"videoCountText":{"simpleText":"45.6K subscribers"}}}`, 45600},
+		{"channel without count", `{"channelRenderer":{"channelId":"UCuAXFkgsw1L7xaCfnd5JJOw","videoCountText":{"simpleText":"312 videos"}}}`, -1},
+		{"video owner", `{"videoRenderer":{"videoId":"dQw4w9WgXcQ","ownerText":{"runs":[{"text":"Owner"}]},"subscriberCountText":{"simpleText":"3B subscribers"}}}`, 3000000000},
+		{"video owner without count", `{"videoRenderer":{"videoId":"dQw4w9WgXcQ","ownerText":{"runs":[{"text":"Owner"}]}}}`, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := parseItemJSON(t, tt.data)
+			if item == nil || item.Author == nil {
+				t.Fatalf("got %+v", item)
+			}
+			got := item.Author.Subscribers
+			if tt.want < 0 {
+				if got != nil {
+					t.Errorf("Subscribers = %d, want nil", *got)
+				}
+				return
+			}
+			if got == nil || *got != tt.want {
+				t.Errorf("Subscribers = %v, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChannelDescription(t *testing.T) {
+	item := parseItemJSON(t, `{"channelRenderer":{"channelId":"UCuAXFkgsw1L7xaCfnd5JJOw","title":{"simpleText":"Lofi Girl"},"descriptionSnippet":{"runs":[
+		{"text":"Beats to relax/study to. Shop: "},
+		{"text":"lofigirl.com","navigationEndpoint":{"urlEndpoint":{"url":"https://www.youtube.com/redirect?q=https%3A%2F%2Flofigirl.com&v=x"}}},
+		{"text":" and "},
+		{"text":"@lofirecords","navigationEndpoint":{"commandMetadata":{"webCommandMetadata":{"url":"/@lofirecords"}}}}
+	]}}}`)
+	if item == nil {
+		t.Fatal("channel was skipped")
+	}
+
+	if want := "Beats to relax/study to. Shop: lofigirl.com and @lofirecords"; item.Description != want {
+		t.Errorf("Description = %q, want %q", item.Description, want)
+	}
+	want := []string{"https://lofigirl.com", "https://www.youtube.com/@lofirecords"}
+	if strings.Join(item.Links, ",") != strings.Join(want, ",") {
+		t.Errorf("Links = %v, want %v", item.Links, want)
+	}
+}
+
+func TestParseLockupVideo(t *testing.T) {
+	lockup := func(badge string) string {
+		return `{"lockupViewModel":{"contentType":"LOCKUP_CONTENT_TYPE_VIDEO","contentId":"dQw4w9WgXcQ",
+			"metadata":{"lockupMetadataViewModel":{"title":{"content":"Lockup video"}}},
+			"contentImage":{"thumbnailViewModel":{
+				"image":{"sources":[{"url":"https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg","width":480,"height":360}]},
+				"overlays":[{"thumbnailBottomOverlayViewModel":{"badges":[{"thumbnailBadgeViewModel":{"text":"` + badge + `"}}]}}]}}}}`
+	}
+
+	tests := []struct {
+		name         string
+		data         string
+		wantDuration string
+		wantSeconds  int
+		wantLive     bool
+	}{
+		{"duration", lockup("3:33"), "3:33", 213, false},
+		{"live", lockup("LIVE"), "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := parseItemJSON(t, tt.data)
+			if item == nil {
+				t.Fatal("lockup was skipped")
+			}
+			if item.Type != "video" || item.ID != "dQw4w9WgXcQ" || item.Name != "Lockup video" {
+				t.Errorf("got %s %q %q", item.Type, item.ID, item.Name)
+			}
+			if len(item.Thumbnails) != 1 || item.Thumbnail != "https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg" {
+				t.Errorf("Thumbnails = %+v", item.Thumbnails)
+			}
+			if item.Duration != tt.wantDuration || item.DurationSeconds != tt.wantSeconds || item.IsLive != tt.wantLive {
+				t.Errorf("got Duration %q (%ds) live %v, want %q (%ds) live %v", item.Duration, item.DurationSeconds, item.IsLive, tt.wantDuration, tt.wantSeconds, tt.wantLive)
+			}
+		})
+	}
+}
+
+func TestParseShorts(t *testing.T) {
+	data := `{"contents":{"twoColumnSearchResultsRenderer":{"primaryContents":{"sectionListRenderer":{"contents":[{"itemSectionRenderer":{"contents":[
+		{"videoRenderer":{"videoId":"dQw4w9WgXcQ","title":{"runs":[{"text":"Not a short"}]}}},
+		{"reelShelfRenderer":{"items":[
+			{"shortsLockupViewModel":{"entityId":"shorts-shelf-item-short000001",
+				"onTap":{"innertubeCommand":{"reelWatchEndpoint":{"videoId":"short000001"}}},
+				"overlayMetadata":{"primaryText":{"content":"Lockup short"},"secondaryText":{"content":"1.2M views"}},
+				"thumbnail":{"sources":[{"url":"https://i.ytimg.com/vi/short000001/frame0.jpg","width":405,"height":720}]}}},
+			{"reelItemRenderer":{"videoId":"short000002","headline":{"simpleText":"Reel short"},"viewCountText":{"simpleText":"42K views"}}}
+		]}}
+	]}}]}}}}}`
+
+	opts := DefaultOptions()
+	opts.Type = "short"
+	result, err := parseFixture(t, data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 2 {
+		t.Fatalf("got %d items, want 2 shorts", len(result.Items))
+	}
+
+	wantViews := []int{1200000, 42000}
+	for i, item := range result.Items {
+		if item.Type != "short" || item.URL != BaseShortsURL+item.ID {
+			t.Errorf("item %d: Type %q URL %q", i, item.Type, item.URL)
+		}
+		if item.Views == nil || *item.Views != wantViews[i] {
+			t.Errorf("item %d: Views = %v, want %d", i, item.Views, wantViews[i])
+		}
+		if item.Duration != "" || item.DurationSeconds != 0 {
+			t.Errorf("item %d: Duration = %q (%ds), want none", i, item.Duration, item.DurationSeconds)
+		}
+	}
+	if result.Items[0].Name != "Lockup short" || result.Items[1].Name != "Reel short" {
+		t.Errorf("names = %q, %q", result.Items[0].Name, result.Items[1].Name)
+	}
+}
+
+func TestParseMovie(t *testing.T) {
+	data := `{"contents":{"twoColumnSearchResultsRenderer":{"primaryContents":{"sectionListRenderer":{"contents":[{"itemSectionRenderer":{"contents":[
+		{"videoRenderer":{"videoId":"dQw4w9WgXcQ","title":{"runs":[{"text":"Inception trailer"}]}}},
+		{"movieRenderer":{"videoId":"YoHD9XEInc0","title":{"runs":[{"text":"Inception"}]},
+			"lengthText":{"simpleText":"2:28:07"},
+			"descriptionSnippet":{"runs":[{"text":"A thief who steals corporate secrets "},{"text":"through dream-sharing technology."}]},
+			"bottomMetadataItems":[{"simpleText":"Action • 2010 • PG-13"}],
+			"thumbnail":{"thumbnails":[{"url":"https://i.ytimg.com/vi/YoHD9XEInc0/movieposter.jpg","width":240,"height":360}]}}}
+	]}}]}}}}}`
+
+	opts := DefaultOptions()
+	opts.Type = "movie"
+	result, err := parseFixture(t, data, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 1 {
+		t.Fatalf("got %d items, want the movie only", len(result.Items))
+	}
+
+	movie := result.Items[0]
+	if movie.Type != "movie" || movie.ID != "YoHD9XEInc0" || movie.Name != "Inception" {
+		t.Errorf("got %s %q %q", movie.Type, movie.ID, movie.Name)
+	}
+	if movie.Duration != "2:28:07" || movie.DurationSeconds != 8887 {
+		t.Errorf("Duration = %q (%ds)", movie.Duration, movie.DurationSeconds)
+	}
+	if movie.Description != "A thief who steals corporate secrets through dream-sharing technology." {
+		t.Errorf("Description = %q", movie.Description)
+	}
+	if movie.Year != 2010 || movie.Genre != "Action" {
+		t.Errorf("Year %d Genre %q, want 2010 Action", movie.Year, movie.Genre)
+	}
+}
+
+func TestParseOwnerVerified(t *testing.T) {
+	tests := []struct {
+		name  string
+		badge string
+		want  bool
+	}{
+		{"tooltip", `{"metadataBadgeRenderer":{"tooltip":"Verified"}}`, true},
+		{"artist style only", `{"metadataBadgeRenderer":{"style":"BADGE_STYLE_TYPE_VERIFIED_ARTIST"}}`, true},
+		{"verified style only", `{"metadataBadgeRenderer":{"style":"BADGE_STYLE_TYPE_VERIFIED"}}`, true},
+		{"other badge", `{"metadataBadgeRenderer":{"style":"BADGE_STYLE_TYPE_SIMPLE","label":"New"}}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := parseItemJSON(t, `{"playlistRenderer":{"playlistId":"PLbpi6ZahB8FHfSfvTrTAlSFakdwsVQ5GP",
+				"shortBylineText":{"runs":[{"text":"Artist","navigationEndpoint":{"browseEndpoint":{"browseId":"UCuAXFkgsw1L7xaCfnd5JJOw"}}}]},
+				"ownerBadges":[`+tt.badge+`]}}`)
+			if item == nil || item.Owner == nil {
+				t.Fatalf("got %+v", item)
+			}
+			if item.Owner.Verified != tt.want {
+				t.Errorf("Verified = %v, want %v", item.Owner.Verified, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDurationSeconds(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+		live bool
+	}{
+		{"minutes", `{"videoRenderer":{"videoId":"dQw4w9WgXcQ","lengthText":{"simpleText":"3:33"}}}`, 213, false},
+		{"hours", `{"videoRenderer":{"videoId":"dQw4w9WgXcQ","lengthText":{"simpleText":"1:02:03"}}}`, 3723, false},
+		{"live without duration", `{"videoRenderer":{"videoId":"dQw4w9WgXcQ","badges":[{"metadataBadgeRenderer":{"label":"LIVE"}}]}}`, 0, true},
+		{"no duration", `{"videoRenderer":{"videoId":"dQw4w9WgXcQ"}}`, 0, false},
+		{"lockup lengthSeconds", `{"lockupViewModel":{"contentType":"LOCKUP_CONTENT_TYPE_VIDEO","contentId":"dQw4w9WgXcQ","lengthSeconds":"3723","contentImage":{"thumbnailViewModel":{}}}}`, 3723, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := parseItemJSON(t, tt.data)
+			if item == nil {
+				t.Fatal("item was skipped")
+			}
+			if item.DurationSeconds != tt.want || item.IsLive != tt.live {
+				t.Errorf("DurationSeconds = %d live %v, want %d live %v", item.DurationSeconds, item.IsLive, tt.want, tt.live)
+			}
+		})
+	}
+}
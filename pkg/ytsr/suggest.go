@@ -0,0 +1,87 @@
+package ytsr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const BaseSuggestURL = "https://suggestqueries.google.com/complete/search"
+
+func Suggestions(query string, options *Options) ([]string, error) {
+	if query == "" {
+		return nil, errors.New("query is mandatory")
+	}
+
+	opts := checkArgs(query, options)
+
+	params := url.Values{}
+	params.Set("client", "youtube")
+	params.Set("ds", "yt")
+	params.Set("q", query)
+	params.Set("gl", opts.GL)
+	params.Set("hl", opts.HL)
+
+	req, err := http.NewRequestWithContext(requestContext(opts), "GET", BaseSuggestURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	if opts.DryRun {
+		return nil, dryRun(req, nil)
+	}
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp.Body, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSuggestions(string(body))
+}
+
+func parseSuggestions(body string) ([]string, error) {
+	start := strings.Index(body, "(")
+	end := strings.LastIndex(body, ")")
+	if start != -1 && end > start {
+		body = body[start+1 : end]
+	}
+
+	var data []interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return nil, fmt.Errorf("invalid suggestions response: %v", err)
+	}
+	if len(data) < 2 {
+		return nil, fmt.Errorf("invalid suggestions response")
+	}
+
+	entries, ok := data[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid suggestions response")
+	}
+
+	suggestions := []string{}
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case string:
+			suggestions = append(suggestions, e)
+		case []interface{}:
+			if len(e) > 0 {
+				if text, ok := e[0].(string); ok {
+					suggestions = append(suggestions, text)
+				}
+			}
+		}
+	}
+
+	return suggestions, nil
+}
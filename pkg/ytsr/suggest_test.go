@@ -0,0 +1,85 @@
+package ytsr
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var fixtureSuggestions = []string{"lofi", "lofi hip hop", "lofi girl", "lofi music", "lofi & chill"}
+
+func TestParseSuggestions(t *testing.T) {
+	body, err := os.ReadFile("testdata/suggestions.jsonp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseSuggestions(string(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, fixtureSuggestions) {
+		t.Errorf("parseSuggestions() = %q, want %q", got, fixtureSuggestions)
+	}
+}
+
+func TestParseSuggestionsInvalid(t *testing.T) {
+	for _, body := range []string{"", "window.google.ac.h(", `["lofi"]`, `["lofi", "nope"]`} {
+		if _, err := parseSuggestions(body); err == nil {
+			t.Errorf("parseSuggestions(%q) returned no error", body)
+		}
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	body, err := os.ReadFile("testdata/suggestions.jsonp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("client") != "youtube" || query.Get("q") != "lofi" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(string(body))),
+		}, nil
+	})}
+
+	got, err := Suggestions("lofi", &Options{Client: client})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, fixtureSuggestions) {
+		t.Errorf("Suggestions() = %q, want %q", got, fixtureSuggestions)
+	}
+}
+
+func TestSuggestionsDryRun(t *testing.T) {
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("dry run sent %s", req.URL)
+		return nil, errors.New("unexpected request")
+	})}
+
+	_, err := Suggestions("lofi", &Options{Client: client, DryRun: true, APIHost: "http://127.0.0.1:1"})
+	var dryErr *dryRunError
+	if !errors.As(err, &dryErr) {
+		t.Fatalf("got %v, want a dry run error", err)
+	}
+	if !strings.HasPrefix(dryErr.request.URL, BaseSuggestURL+"?") {
+		t.Errorf("dry run URL = %q, want the suggest endpoint", dryErr.request.URL)
+	}
+}
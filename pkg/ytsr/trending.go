@@ -0,0 +1,98 @@
+package ytsr
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const BaseBrowseURL = "https://www.youtube.com/youtubei/v1/browse"
+
+var trendingCategories = map[string]string{
+	"music":  "4gINGgt5dG1hX2NoYXJ0cw%3D%3D",
+	"gaming": "4gIcGhpnYW1pbmdfY29ycHVzX21vc3RfcG9wdWxhcg%3D%3D",
+	"movies": "4gIKGgh0cmFpbGVycw%3D%3D",
+}
+
+func GetTrending(options *Options) (*SearchResult, error) {
+	opts := checkArgs("FEtrending", options)
+
+	payload := map[string]interface{}{
+		"browseId": "FEtrending",
+	}
+
+	if opts.TrendingCategory != "" {
+		param, ok := trendingCategories[opts.TrendingCategory]
+		if !ok {
+			return nil, fmt.Errorf("unknown trending category %q", opts.TrendingCategory)
+		}
+		params, err := url.QueryUnescape(param)
+		if err != nil {
+			return nil, err
+		}
+		payload["params"] = params
+	}
+
+	clientVersion, _ := opts.Cache.get()
+	if clientVersion == "" {
+		clientVersion = defaultClientVersion
+	}
+	payload["context"] = buildPostContext(clientVersion, opts)
+
+	jsonResp, err := doPost(BaseBrowseURL, opts, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTrendingResponse(jsonResp, opts)
+}
+
+func parseTrendingResponse(jsonResp map[string]interface{}, opts *Options) (*SearchResult, error) {
+	twoCol, ok := findContentsRenderer(jsonResp["contents"], "twoColumnBrowseResultsRenderer")
+	if !ok {
+		return nil, fmt.Errorf("invalid trending response format")
+	}
+
+	tabs, _ := twoCol["tabs"].([]interface{})
+	var sectionList map[string]interface{}
+	for _, tab := range tabs {
+		tabMap, _ := tab.(map[string]interface{})
+		tabRenderer, ok := tabMap["tabRenderer"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		content, _ := tabRenderer["content"].(map[string]interface{})
+		if list, ok := content["sectionListRenderer"].(map[string]interface{}); ok {
+			if selected, _ := tabRenderer["selected"].(bool); selected || sectionList == nil {
+				sectionList = list
+			}
+		}
+	}
+	if sectionList == nil {
+		return nil, fmt.Errorf("invalid trending response format")
+	}
+
+	var rawItems []interface{}
+	contents, _ := sectionList["contents"].([]interface{})
+	for _, content := range contents {
+		contentMap, _ := content.(map[string]interface{})
+		if itemSection, ok := contentMap["itemSectionRenderer"].(map[string]interface{}); ok {
+			if items, ok := itemSection["contents"].([]interface{}); ok {
+				rawItems = append(rawItems, items...)
+			}
+		}
+	}
+
+	result := &SearchResult{
+		Items: []SearchItem{},
+	}
+
+	if err := parseItems(rawItems, opts, result); err != nil {
+		return nil, err
+	}
+
+	if opts.KeepRawJSON {
+		result.Raw = jsonResp
+	}
+
+	return result, nil
+}
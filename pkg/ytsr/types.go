@@ -1,6 +1,11 @@
 package ytsr
 
-import "sync"
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
 
 type Cache struct {
 	mu             sync.RWMutex
@@ -9,36 +14,130 @@ type Cache struct {
 }
 
 type Options struct {
-	Query      string
+	Query                   string
+	Type                    string
+	Limit                   int
+	SafeSearch              bool
+	GL                      string
+	HL                      string
+	UTCOffset               int
+	ExtractInitialData      func(body string) (string, bool)
+	StrictParsing           bool
+	Client                  *http.Client
+	Filters                 *Filters
+	LiveOnly                bool
+	MaxTotalBytes           int64
+	InsecureSkipVerify      bool
+	DisableHTTP2            bool
+	PreferredThumbnailWidth int
+	OmitThumbnails          bool
+	UnmarshalJSON           func(data []byte, v interface{}) error
+	Retries                 int // zero means the default of 3; NoRetries disables retrying
+	RetryOnEmpty            bool
+	KeepRawJSON             bool
+	APIHost                 string
+	TrendingCategory        string
+	DryRun                  bool
+	RetryBackoff            time.Duration
+	ExponentialBackoff      bool
+	RequestContext          context.Context
+	FilterFunc              func(item SearchItem) bool
+	OnItem                  func(item SearchItem) bool
+	Cache                   *Cache
+	ExtraParams             map[string]string
+	Timeout                 time.Duration
+
+	bytesRead int64
+	sp        string
+	stopped   bool
+	truncated bool
+}
+
+type Filter struct {
+	Label  string
+	Status string
+	Param  string
+	URL    string
+}
+
+type Filters struct {
+	UploadDate string
+	Duration   string
 	Type       string
-	Limit      int
-	SafeSearch bool
-	GL         string
-	HL         string
-	UTCOffset  int
+	Features   []string
+	SortBy     string
 }
 
 type SearchResult struct {
-	Query   string
-	Items   []SearchItem
-	Results int
+	Query              string
+	CorrectedQuery     string
+	OriginalQuery      string
+	Items              []SearchItem
+	Results            int
+	AppliedFilter      string
+	Stats              ParseStats
+	Continuation       string
+	Cursor             *SearchCursor
+	PartialDueToRegion bool
+	Raw                map[string]interface{}
+	DryRun             *DryRunRequest
+}
+
+type DryRunRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+type dryRunError struct {
+	request *DryRunRequest
+}
+
+func (e *dryRunError) Error() string {
+	return "dry run: " + e.request.Method + " " + e.request.URL
+}
+
+type SearchCursor struct {
+	Token         string `json:"token"`
+	ClientVersion string `json:"clientVersion"`
+	Query         string `json:"query"`
+	GL            string `json:"gl"`
+	HL            string `json:"hl"`
+}
+
+type ParseStats struct {
+	Parsed             int
+	SkippedUnknown     int
+	SkippedUnavailable int
 }
 
 type SearchItem struct {
-	Type        string
-	ID          string
-	URL         string
-	Name        string
-	Description string
-	Duration    string
-	Thumbnail   string
-	Thumbnails  []Thumbnail
-	UploadedAt  string
-	Views       *int
-	Author      *Author
-	IsLive      bool
-	Badges      []string
-	Owner       *Owner
+	Type             string
+	ID               string
+	URL              string
+	Name             string
+	Description      string
+	Duration         string
+	DurationSeconds  int
+	Thumbnail        string
+	Thumbnails       []Thumbnail
+	MovingThumbnails []Thumbnail
+	UploadedAt       string
+	Views            *int
+	Author           *Author
+	IsLive           bool
+	IsUpcoming       bool
+	ScheduledStart   time.Time
+	IsMembersOnly    bool
+	HasCaptions      bool
+	Is4K             bool
+	Year             int
+	Genre            string
+	Links            []string
+	Length           int
+	Badges           []string
+	Owner            *Owner
 }
 
 type Thumbnail struct {
@@ -48,6 +147,17 @@ type Thumbnail struct {
 }
 
 type Author struct {
+	Name        string
+	ChannelID   string
+	URL         string
+	BestAvatar  *Thumbnail
+	Avatars     []Thumbnail
+	Verified    bool
+	Badges      []string
+	Subscribers *int
+}
+
+type Owner struct {
 	Name       string
 	ChannelID  string
 	URL        string
@@ -57,14 +167,6 @@ type Author struct {
 	Badges     []string
 }
 
-type Owner struct {
-	Name      string
-	ChannelID string
-	URL       string
-	Verified  bool
-	Badges    []string
-}
-
 type Context struct {
 	Client map[string]interface{} `json:"client"`
 	User   map[string]interface{} `json:"user"`